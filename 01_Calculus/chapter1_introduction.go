@@ -1,7 +1,10 @@
 // 2026 Update: Calculus Introduction
 package calculus
 
-import "math"
+import (
+	"math"
+	"math/rand/v2"
+)
 
 type Function func(float64) float64
 
@@ -13,7 +16,8 @@ type Piece struct {
 }
 
 type RNG struct {
-	state uint64
+	state  uint64
+	source rand.Source
 }
 
 func NewRNG(seed uint64) *RNG {
@@ -23,11 +27,24 @@ func NewRNG(seed uint64) *RNG {
 	return &RNG{state: seed}
 }
 
+// NewRNGFromSource draws from src, such as a 16_Random generator, instead of
+// the built-in LCG.
+func NewRNGFromSource(src rand.Source) *RNG {
+	return &RNG{source: src}
+}
+
 func (r *RNG) Next() uint64 {
+	if r.source != nil {
+		return r.source.Uint64()
+	}
 	r.state = r.state*6364136223846793005 + 1442695040888963407
 	return r.state
 }
 
+func (r *RNG) Uint64() uint64 {
+	return r.Next()
+}
+
 func (r *RNG) Float64() float64 {
 	return float64(r.Next()>>11) / float64(1<<53)
 }
@@ -176,7 +176,10 @@ func RightEndpointRule(f Function, a, b float64) float64 {
 }
 
 func MonteCarloIntegration(f Function, a, b float64, samples int, seed uint64) float64 {
-	rng := NewRNG(seed)
+	return MonteCarloIntegrationWithRNG(f, a, b, samples, NewRNG(seed))
+}
+
+func MonteCarloIntegrationWithRNG(f Function, a, b float64, samples int, rng *RNG) float64 {
 	sum := 0.0
 	for i := 0; i < samples; i++ {
 		x := a + rng.Float64()*(b-a)
@@ -186,7 +189,10 @@ func MonteCarloIntegration(f Function, a, b float64, samples int, seed uint64) f
 }
 
 func MonteCarloImportance(f Function, sampler func(*RNG) float64, weight func(float64) float64, samples int, seed uint64) float64 {
-	rng := NewRNG(seed)
+	return MonteCarloImportanceWithRNG(f, sampler, weight, samples, NewRNG(seed))
+}
+
+func MonteCarloImportanceWithRNG(f Function, sampler func(*RNG) float64, weight func(float64) float64, samples int, rng *RNG) float64 {
 	sum := 0.0
 	for i := 0; i < samples; i++ {
 		x := sampler(rng)
@@ -0,0 +1,350 @@
+// 2026 Update: Incomplete Gamma and Beta
+package functions
+
+import "math"
+
+// RegularizedGammaP returns P(a, x) = γ(a, x)/Γ(a) for a > 0 and x >= 0.
+func RegularizedGammaP(a, x float64) float64 {
+	p, _ := incompleteGamma(a, x)
+	return p
+}
+
+// RegularizedGammaQ returns Q(a, x) = 1 - P(a, x), computed without
+// cancellation in the upper tail.
+func RegularizedGammaQ(a, x float64) float64 {
+	_, q := incompleteGamma(a, x)
+	return q
+}
+
+// InverseRegularizedGammaP returns x such that P(a, x) = p.
+func InverseRegularizedGammaP(a, p float64) float64 {
+	return inverseIncompleteGamma(a, p, 1-p)
+}
+
+// InverseRegularizedGammaQ returns x such that Q(a, x) = q.
+func InverseRegularizedGammaQ(a, q float64) float64 {
+	return inverseIncompleteGamma(a, 1-q, q)
+}
+
+// RegularizedBeta returns I_x(a, b) = B(x; a, b)/B(a, b) for a, b > 0 and
+// 0 <= x <= 1.
+func RegularizedBeta(a, b, x float64) float64 {
+	w, _ := incompleteBeta(a, b, x, 1-x)
+	return w
+}
+
+// InverseRegularizedBeta returns x such that I_x(a, b) = p.
+func InverseRegularizedBeta(a, b, p float64) float64 {
+	return inverseIncompleteBeta(a, b, p, 1-p)
+}
+
+// incompleteGamma returns P(a, x) and Q(a, x), computing the smaller one
+// directly: the power series below x = a+1 and the continued fraction above.
+func incompleteGamma(a, x float64) (float64, float64) {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(x) || a <= 0 || x < 0:
+		return math.NaN(), math.NaN()
+	case x == 0:
+		return 0, 1
+	case math.IsInf(x, 1):
+		return 1, 0
+	}
+	if x < a+1 {
+		p := gammaSeries(a, x)
+		return p, 1 - p
+	}
+	q := gammaFraction(a, x)
+	return 1 - q, q
+}
+
+func gammaSeries(a, x float64) float64 {
+	sum, term := 1.0, 1.0
+	for n := 1; n < 100000; n++ {
+		term *= x / (a + float64(n))
+		sum += term
+		if term < sum*1e-17 {
+			break
+		}
+	}
+	return gammaPrefix(a, x) * sum / a
+}
+
+// gammaFraction evaluates Q(a, x) with the modified Lentz algorithm.
+func gammaFraction(a, x float64) float64 {
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < 100000; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < 1e-16 {
+			break
+		}
+	}
+	return gammaPrefix(a, x) * h
+}
+
+// gammaPrefix returns x^a e^-x / Γ(a). For large a the Stirling form keeps
+// the exponent free of the cancellation between a·ln x, x and ln Γ(a).
+func gammaPrefix(a, x float64) float64 {
+	if a < 10 {
+		lg, _ := math.Lgamma(a)
+		return math.Exp(a*math.Log(x) - x - lg)
+	}
+	return math.Sqrt(a/(2*math.Pi)) * math.Exp(a*logRatioMinusOne(x, a)-stirlingError(a))
+}
+
+// incompleteBeta returns I_x(a, b) and 1 - I_x(a, b), given both x and
+// y = 1 - x so callers near 1 keep full precision.
+func incompleteBeta(a, b, x, y float64) (float64, float64) {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(x) || a <= 0 || b <= 0 || x < 0 || x > 1:
+		return math.NaN(), math.NaN()
+	case x == 0:
+		return 0, 1
+	case y == 0:
+		return 1, 0
+	}
+	if x < (a+1)/(a+b+2) {
+		w := betaPrefix(a, b, x, y) * betaFraction(a, b, x) / a
+		return w, 1 - w
+	}
+	wc := betaPrefix(b, a, y, x) * betaFraction(b, a, y) / b
+	return 1 - wc, wc
+}
+
+// betaPrefix returns x^a y^b / B(a, b).
+func betaPrefix(a, b, x, y float64) float64 {
+	if a >= 10 && b >= 10 {
+		c := a + b
+		e := a*logRatioMinusOne(x, a/c) + b*logRatioMinusOne(y, b/c)
+		return math.Sqrt(a*b/(2*math.Pi*c)) * math.Exp(e-stirlingError(a)-stirlingError(b)+stirlingError(c))
+	}
+	return math.Exp(a*math.Log(x) + b*math.Log(y) - logBeta(a, b))
+}
+
+func betaFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m < 100000; m++ {
+		fm := float64(m)
+		delta := 1.0
+		for _, aa := range [2]float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + aa*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + aa/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			delta = d * c
+			h *= delta
+		}
+		if math.Abs(delta-1) < 1e-16 {
+			break
+		}
+	}
+	return h
+}
+
+// logBeta returns ln B(a, b), avoiding the cancellation between ln Γ(a) and
+// ln Γ(a+b) when one argument is large.
+func logBeta(a, b float64) float64 {
+	if a < b {
+		a, b = b, a
+	}
+	if a < 10 {
+		la, _ := math.Lgamma(a)
+		lb, _ := math.Lgamma(b)
+		lc, _ := math.Lgamma(a + b)
+		return la + lb - lc
+	}
+	lb, _ := math.Lgamma(b)
+	return lb - (a-0.5)*math.Log1p(b/a) - b*math.Log(a+b) + b + stirlingError(a) - stirlingError(a+b)
+}
+
+// stirlingError returns ln Γ(a) - [(a-½)ln a - a + ½ln 2π] for a >= 10.
+func stirlingError(a float64) float64 {
+	f := 1 / (a * a)
+	return (1.0/12 - f*(1.0/360-f*(1.0/1260-f*(1.0/1680-f*(1.0/1188-f*691.0/360360))))) / a
+}
+
+// logRatioMinusOne returns ln(x/x0) - (x/x0 - 1), using the series for
+// ln(1+t) - t when x is close to x0.
+func logRatioMinusOne(x, x0 float64) float64 {
+	t := (x - x0) / x0
+	if math.Abs(t) > 0.5 {
+		return math.Log(x/x0) - t
+	}
+	sum, term := 0.0, t
+	for n := 2; n < 200; n++ {
+		term *= -t
+		next := sum + term/float64(n)
+		if next == sum {
+			break
+		}
+		sum = next
+	}
+	return sum
+}
+
+func inverseIncompleteGamma(a, p, q float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(p) || a <= 0 || p < 0 || q < 0:
+		return math.NaN()
+	case p == 0:
+		return 0
+	case q == 0:
+		return math.Inf(1)
+	}
+	x := inverseGammaGuess(a, p, q)
+	if x == 0 {
+		return 0
+	}
+	lo, hi := 0.0, math.Inf(1)
+	for i := 0; i < 500; i++ {
+		P, Q := incompleteGamma(a, x)
+		f := P - p
+		if p > q {
+			f = q - Q
+		}
+		if f == 0 {
+			return x
+		}
+		if f < 0 {
+			lo = x
+		} else {
+			hi = x
+		}
+		u := f / (gammaPrefix(a, x) / x)
+		step := u / (1 - 0.5*math.Min(1, u*((a-1)/x-1)))
+		if math.Abs(step) <= 1e-15*x {
+			return x - step
+		}
+		x -= step
+		if !(x > lo && x < hi) {
+			if math.IsInf(hi, 1) {
+				x = 2 * lo
+			} else {
+				x = 0.5 * (lo + hi)
+			}
+		}
+	}
+	return x
+}
+
+func inverseGammaGuess(a, p, q float64) float64 {
+	lg, _ := math.Lgamma(a + 1)
+	if small := math.Exp((math.Log(p) + lg) / a); small < 0.2*(a+1) {
+		return small
+	}
+	if a > 1 {
+		z := roughNormalQuantile(p, q)
+		x := a * math.Pow(1-1/(9*a)+z/(3*math.Sqrt(a)), 3)
+		return math.Max(x, 1e-3*a)
+	}
+	t := 1 - a*(0.253+a*0.12)
+	if p < t {
+		return math.Pow(p/t, 1/a)
+	}
+	return 1 - math.Log(q/(1-t))
+}
+
+func inverseIncompleteBeta(a, b, p, q float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b) || math.IsNaN(p) || a <= 0 || b <= 0 || p < 0 || q < 0:
+		return math.NaN()
+	case p == 0:
+		return 0
+	case q == 0:
+		return 1
+	}
+	x := inverseBetaGuess(a, b, p, q)
+	if x == 0 || x == 1 {
+		return x
+	}
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 500; i++ {
+		w, wc := incompleteBeta(a, b, x, 1-x)
+		f := w - p
+		if p > q {
+			f = q - wc
+		}
+		if f == 0 {
+			return x
+		}
+		if f < 0 {
+			lo = x
+		} else {
+			hi = x
+		}
+		y := 1 - x
+		u := f / (betaPrefix(a, b, x, y) / (x * y))
+		step := u / (1 - 0.5*math.Min(1, u*((a-1)/x-(b-1)/y)))
+		if math.Abs(step) <= 1e-15*x {
+			return x - step
+		}
+		x -= step
+		if !(x > lo && x < hi) {
+			x = 0.5 * (lo + hi)
+		}
+	}
+	return x
+}
+
+func inverseBetaGuess(a, b, p, q float64) float64 {
+	if small := math.Exp((math.Log(p*a) + logBeta(a, b)) / a); small < 0.1*a/(a+b) {
+		return small
+	}
+	if large := math.Exp((math.Log(q*b) + logBeta(a, b)) / b); large < 0.1*b/(a+b) {
+		return 1 - large
+	}
+	if a >= 1 && b >= 1 {
+		z := roughNormalQuantile(p, q)
+		al := (z*z - 3) / 6
+		h := 2 / (1/(2*a-1) + 1/(2*b-1))
+		w := z*math.Sqrt(al+h)/h - (1/(2*b-1)-1/(2*a-1))*(al+5.0/6-2/(3*h))
+		return a / (a + b*math.Exp(2*w))
+	}
+	t := math.Exp(a*math.Log(a/(a+b))) / a
+	u := math.Exp(b*math.Log(b/(a+b))) / b
+	if w := t + u; p < t/w {
+		return math.Pow(a*w*p, 1/a)
+	}
+	return 1 - math.Pow(b*(t+u)*q, 1/b)
+}
+
+// roughNormalQuantile is a starting value only (absolute error < 3e-3).
+func roughNormalQuantile(p, q float64) float64 {
+	pp := math.Min(p, q)
+	t := math.Sqrt(-2 * math.Log(pp))
+	z := t - (2.30753+t*0.27061)/(1+t*(0.99229+t*0.04481))
+	if p < q {
+		return -z
+	}
+	return z
+}
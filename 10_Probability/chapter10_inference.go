@@ -0,0 +1,235 @@
+// 2026 Update: Hypothesis Test Results
+package probability
+
+import "math"
+
+type Alternative int
+
+const (
+	TwoSided Alternative = iota
+	Less
+	Greater
+)
+
+// TestResult reports a hypothesis test. DF and DF2 are zero when the reference
+// distribution has no degrees of freedom, and DF2 is only set for F tests.
+// The confidence interval is for Estimate, with an infinite end for one-sided
+// alternatives; tests without a natural estimate leave Estimate and the
+// interval NaN.
+type TestResult struct {
+	Statistic  float64
+	DF         float64
+	DF2        float64
+	PValue     float64
+	Estimate   float64
+	CILower    float64
+	CIUpper    float64
+	EffectSize float64
+}
+
+// OneSampleTTest tests the mean of data against mu0. EffectSize is Cohen's d.
+func OneSampleTTest(data []float64, mu0 float64, alt Alternative, confidence float64) TestResult {
+	n := float64(len(data))
+	mean := Mean(data)
+	sd := math.Sqrt(SampleVariance(data))
+	return tTestResult(mean, mu0, sd/math.Sqrt(n), n-1, (mean-mu0)/sd, alt, confidence)
+}
+
+// PairedTTest tests the mean of x[i] - y[i] against zero. EffectSize is
+// Cohen's d of the differences.
+func PairedTTest(x, y []float64, alt Alternative, confidence float64) TestResult {
+	diff := make([]float64, len(x))
+	for i := range x {
+		diff[i] = x[i] - y[i]
+	}
+	return OneSampleTTest(diff, 0, alt, confidence)
+}
+
+// WelchTTest tests mean(x) - mean(y) against zero without assuming equal
+// variances. EffectSize is Cohen's d with the pooled standard deviation.
+func WelchTTest(x, y []float64, alt Alternative, confidence float64) TestResult {
+	nx, ny := float64(len(x)), float64(len(y))
+	vx, vy := SampleVariance(x), SampleVariance(y)
+	sx, sy := vx/nx, vy/ny
+	df := (sx + sy) * (sx + sy) / (sx*sx/(nx-1) + sy*sy/(ny-1))
+	pooled := math.Sqrt(((nx-1)*vx + (ny-1)*vy) / (nx + ny - 2))
+	diff := Mean(x) - Mean(y)
+	return tTestResult(diff, 0, math.Sqrt(sx+sy), df, diff/pooled, alt, confidence)
+}
+
+func tTestResult(estimate, null, se, df, effect float64, alt Alternative, confidence float64) TestResult {
+	d := StudentT{Nu: df}
+	stat := (estimate - null) / se
+	lo, hi := confidenceInterval(d, estimate, se, confidence, alt)
+	return TestResult{
+		Statistic:  stat,
+		DF:         df,
+		PValue:     pValue(d, stat, alt),
+		Estimate:   estimate,
+		CILower:    lo,
+		CIUpper:    hi,
+		EffectSize: effect,
+	}
+}
+
+// OneWayANOVA returns the F test for equal group means. EffectSize is η².
+func OneWayANOVA(groups ...[]float64) TestResult {
+	all := []float64{}
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	grand := Mean(all)
+	ssb, ssw := 0.0, 0.0
+	for _, g := range groups {
+		m := Mean(g)
+		ssb += float64(len(g)) * (m - grand) * (m - grand)
+		for _, v := range g {
+			ssw += (v - m) * (v - m)
+		}
+	}
+	dfb, dfw := float64(len(groups)-1), float64(len(all)-len(groups))
+	f := (ssb / dfb) / (ssw / dfw)
+	return TestResult{
+		Statistic:  f,
+		DF:         dfb,
+		DF2:        dfw,
+		PValue:     FisherF{D1: dfb, D2: dfw}.Survival(f),
+		Estimate:   math.NaN(),
+		CILower:    math.NaN(),
+		CIUpper:    math.NaN(),
+		EffectSize: ssb / (ssb + ssw),
+	}
+}
+
+// ChiSquareGoodnessOfFit compares observed counts with expected counts or
+// probabilities, rescaling expected to the observed total. EffectSize is
+// Cohen's w.
+func ChiSquareGoodnessOfFit(observed, expected []float64) TestResult {
+	total, expectedTotal := 0.0, 0.0
+	for i := range observed {
+		total += observed[i]
+		expectedTotal += expected[i]
+	}
+	chi2 := 0.0
+	for i, o := range observed {
+		e := expected[i] * total / expectedTotal
+		chi2 += (o - e) * (o - e) / e
+	}
+	return chiSquareResult(chi2, float64(len(observed)-1), math.Sqrt(chi2/total))
+}
+
+// ChiSquareIndependence tests independence of rows and columns in a
+// contingency table of counts. EffectSize is Cramér's V.
+func ChiSquareIndependence(table [][]float64) TestResult {
+	r, c := len(table), len(table[0])
+	rowSums := make([]float64, r)
+	colSums := make([]float64, c)
+	total := 0.0
+	for i, row := range table {
+		for j, v := range row {
+			rowSums[i] += v
+			colSums[j] += v
+			total += v
+		}
+	}
+	chi2 := 0.0
+	for i, row := range table {
+		for j, v := range row {
+			e := rowSums[i] * colSums[j] / total
+			chi2 += (v - e) * (v - e) / e
+		}
+	}
+	k := math.Min(float64(r), float64(c)) - 1
+	return chiSquareResult(chi2, float64((r-1)*(c-1)), math.Sqrt(chi2/(total*k)))
+}
+
+func chiSquareResult(chi2, df, effect float64) TestResult {
+	return TestResult{
+		Statistic:  chi2,
+		DF:         df,
+		PValue:     ChiSquared{K: df}.Survival(chi2),
+		Estimate:   math.NaN(),
+		CILower:    math.NaN(),
+		CIUpper:    math.NaN(),
+		EffectSize: effect,
+	}
+}
+
+// OneProportionZTest tests a binomial proportion against p0 with the score
+// statistic and reports the Wilson interval. EffectSize is Cohen's h.
+func OneProportionZTest(successes, n int, p0 float64, alt Alternative, confidence float64) TestResult {
+	nf := float64(n)
+	p := float64(successes) / nf
+	z := (p - p0) / math.Sqrt(p0*(1-p0)/nf)
+	lo, hi := wilsonInterval(p, nf, confidence, alt)
+	return TestResult{
+		Statistic:  z,
+		PValue:     pValue(Normal{Mu: 0, Sigma: 1}, z, alt),
+		Estimate:   p,
+		CILower:    lo,
+		CIUpper:    hi,
+		EffectSize: cohenH(p, p0),
+	}
+}
+
+// TwoProportionZTest tests p1 - p2 against zero using the pooled proportion;
+// the interval for the difference uses the unpooled standard error.
+func TwoProportionZTest(x1, n1, x2, n2 int, alt Alternative, confidence float64) TestResult {
+	m1, m2 := float64(n1), float64(n2)
+	p1, p2 := float64(x1)/m1, float64(x2)/m2
+	pooled := float64(x1+x2) / (m1 + m2)
+	z := (p1 - p2) / math.Sqrt(pooled*(1-pooled)*(1/m1+1/m2))
+	normal := Normal{Mu: 0, Sigma: 1}
+	se := math.Sqrt(p1*(1-p1)/m1 + p2*(1-p2)/m2)
+	lo, hi := confidenceInterval(normal, p1-p2, se, confidence, alt)
+	return TestResult{
+		Statistic:  z,
+		PValue:     pValue(normal, z, alt),
+		Estimate:   p1 - p2,
+		CILower:    lo,
+		CIUpper:    hi,
+		EffectSize: cohenH(p1, p2),
+	}
+}
+
+func pValue(d Distribution, stat float64, alt Alternative) float64 {
+	switch alt {
+	case Less:
+		return d.CDF(stat)
+	case Greater:
+		return d.Survival(stat)
+	}
+	return math.Min(1, 2*math.Min(d.CDF(stat), d.Survival(stat)))
+}
+
+func confidenceInterval(d Distribution, estimate, se, confidence float64, alt Alternative) (float64, float64) {
+	switch alt {
+	case Less:
+		return math.Inf(-1), estimate + se*d.Quantile(confidence)
+	case Greater:
+		return estimate - se*d.Quantile(confidence), math.Inf(1)
+	}
+	crit := d.Quantile(1 - (1-confidence)/2)
+	return estimate - crit*se, estimate + crit*se
+}
+
+func wilsonInterval(p, n, confidence float64, alt Alternative) (float64, float64) {
+	z := StandardNormalQuantile(1 - (1-confidence)/2)
+	if alt != TwoSided {
+		z = StandardNormalQuantile(confidence)
+	}
+	z2 := z * z
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	half := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+	switch alt {
+	case Less:
+		return 0, center + half
+	case Greater:
+		return center - half, 1
+	}
+	return center - half, center + half
+}
+
+func cohenH(p1, p2 float64) float64 {
+	return 2*math.Asin(math.Sqrt(p1)) - 2*math.Asin(math.Sqrt(p2))
+}
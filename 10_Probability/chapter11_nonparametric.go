@@ -0,0 +1,790 @@
+// 2026 Update: Nonparametric and Normality Tests
+package probability
+
+import (
+	"math"
+	"sort"
+)
+
+// Exact null distributions are used for untied samples below these sizes;
+// otherwise the rank tests fall back to normal approximations with tie
+// corrections, and the Kolmogorov–Smirnov tests to the asymptotic Kolmogorov
+// distribution.
+const (
+	exactRankLimit     = 50
+	exactSpearmanLimit = 9
+	exactKSLimit       = 100
+	exactSmirnovLimit  = 10000
+	exactKruskalLimit  = 1e6
+)
+
+// MannWhitneyU tests whether x tends to be larger or smaller than y. The
+// statistic is U for x (R's W), with a continuity correction in the normal
+// approximation. EffectSize is the rank-biserial correlation.
+func MannWhitneyU(x, y []float64, alt Alternative) TestResult {
+	n1, n2 := len(x), len(y)
+	ranks, ties := averageRanks(append(append([]float64{}, x...), y...))
+	r1 := 0.0
+	for _, r := range ranks[:n1] {
+		r1 += r
+	}
+	m1, m2 := float64(n1), float64(n2)
+	u := r1 - m1*(m1+1)/2
+	var p float64
+	if len(ties) == 0 && n1 < exactRankLimit && n2 < exactRankLimit {
+		p = exactPValue(mannWhitneyCounts(n1, n2), int(u), alt)
+	} else {
+		n := m1 + m2
+		sd := math.Sqrt(m1 * m2 / 12 * (n + 1 - tieSum(ties)/(n*(n-1))))
+		p = normalApproxPValue(u, m1*m2/2, sd, alt)
+	}
+	return rankResult(u, p, 2*u/(m1*m2)-1)
+}
+
+// WilcoxonSignedRank tests whether x is centred on mu. Zero differences are
+// dropped; the statistic is the positive rank sum V. EffectSize is the
+// matched-pairs rank-biserial correlation.
+func WilcoxonSignedRank(x []float64, mu float64, alt Alternative) TestResult {
+	diff := []float64{}
+	for _, v := range x {
+		if v != mu {
+			diff = append(diff, v-mu)
+		}
+	}
+	magnitudes := make([]float64, len(diff))
+	for i, d := range diff {
+		magnitudes[i] = math.Abs(d)
+	}
+	ranks, ties := averageRanks(magnitudes)
+	v := 0.0
+	for i, d := range diff {
+		if d > 0 {
+			v += ranks[i]
+		}
+	}
+	n := float64(len(diff))
+	var p float64
+	if len(ties) == 0 && len(diff) == len(x) && len(diff) < exactRankLimit {
+		p = exactPValue(signedRankCounts(len(diff)), int(v), alt)
+	} else {
+		sd := math.Sqrt(n*(n+1)*(2*n+1)/24 - tieSum(ties)/48)
+		p = normalApproxPValue(v, n*(n+1)/4, sd, alt)
+	}
+	return rankResult(v, p, 4*v/(n*(n+1))-1)
+}
+
+// WilcoxonPairedSignedRank applies the signed-rank test to x[i] - y[i].
+func WilcoxonPairedSignedRank(x, y []float64, alt Alternative) TestResult {
+	diff := make([]float64, len(x))
+	for i := range x {
+		diff[i] = x[i] - y[i]
+	}
+	return WilcoxonSignedRank(diff, 0, alt)
+}
+
+// KruskalWallis tests whether the groups come from the same distribution. H
+// is tie corrected; small untied designs use the exact permutation
+// distribution, others the chi-square approximation. EffectSize is η²_H.
+func KruskalWallis(groups ...[]float64) TestResult {
+	all := []float64{}
+	sizes := make([]int, len(groups))
+	for i, g := range groups {
+		all = append(all, g...)
+		sizes[i] = len(g)
+	}
+	ranks, ties := averageRanks(all)
+	n := float64(len(all))
+	sums := make([]float64, len(groups))
+	offset := 0
+	for i, size := range sizes {
+		for _, r := range ranks[offset : offset+size] {
+			sums[i] += r
+		}
+		offset += size
+	}
+	h := kruskalH(sums, sizes, n) / (1 - tieSum(ties)/(n*n*n-n))
+	k := float64(len(groups))
+	df := k - 1
+	var p float64
+	if len(ties) == 0 && multinomialCount(sizes) <= exactKruskalLimit {
+		p = kruskalWallisExact(sizes, h)
+	} else {
+		p = ChiSquared{K: df}.Survival(h)
+	}
+	return TestResult{
+		Statistic:  h,
+		DF:         df,
+		PValue:     p,
+		Estimate:   math.NaN(),
+		CILower:    math.NaN(),
+		CIUpper:    math.NaN(),
+		EffectSize: (h - k + 1) / (n - k),
+	}
+}
+
+// KolmogorovSmirnov compares x with a continuous cdf. The two-sided statistic
+// is sup|F_n - F|; Greater uses sup(F_n - F) and Less sup(F - F_n). Exact
+// p-values follow Marsaglia–Tsang–Wang (two-sided) and Birnbaum–Tingey
+// (one-sided).
+func KolmogorovSmirnov(x []float64, cdf func(float64) float64, alt Alternative) TestResult {
+	sorted := append([]float64{}, x...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	nf := float64(n)
+	dPlus, dMinus := 0.0, 0.0
+	for i, v := range sorted {
+		f := cdf(v)
+		dPlus = math.Max(dPlus, float64(i+1)/nf-f)
+		dMinus = math.Max(dMinus, f-float64(i)/nf)
+	}
+	d := ksStatistic(dPlus, dMinus, alt)
+	exact := n < exactKSLimit && !hasTies(sorted)
+	var p float64
+	switch {
+	case alt == TwoSided && exact:
+		p = 1 - kolmogorovExactCDF(n, d)
+	case alt == TwoSided:
+		p = kolmogorovSurvival(math.Sqrt(nf) * d)
+	case exact:
+		p = smirnovOneSided(n, d)
+	default:
+		p = math.Exp(-2 * nf * d * d)
+	}
+	return ksResult(d, p)
+}
+
+// KolmogorovSmirnovTwoSample compares the empirical distributions of x and y.
+// Greater uses sup(F_x - F_y) and Less sup(F_y - F_x).
+func KolmogorovSmirnovTwoSample(x, y []float64, alt Alternative) TestResult {
+	xs := append([]float64{}, x...)
+	ys := append([]float64{}, y...)
+	sort.Float64s(xs)
+	sort.Float64s(ys)
+	m, n := len(xs), len(ys)
+	dPlus, dMinus := 0.0, 0.0
+	for i, j := 0, 0; i < m || j < n; {
+		v := math.Inf(1)
+		if i < m {
+			v = xs[i]
+		}
+		if j < n && ys[j] < v {
+			v = ys[j]
+		}
+		for i < m && xs[i] == v {
+			i++
+		}
+		for j < n && ys[j] == v {
+			j++
+		}
+		diff := float64(i)/float64(m) - float64(j)/float64(n)
+		dPlus = math.Max(dPlus, diff)
+		dMinus = math.Max(dMinus, -diff)
+	}
+	d := ksStatistic(dPlus, dMinus, alt)
+	combined := append(append([]float64{}, xs...), ys...)
+	sort.Float64s(combined)
+	var p float64
+	if m*n < exactSmirnovLimit && !hasTies(combined) {
+		p = smirnovExact(m, n, d, alt)
+	} else {
+		ne := float64(m) * float64(n) / float64(m+n)
+		if alt == TwoSided {
+			p = kolmogorovSurvival(math.Sqrt(ne) * d)
+		} else {
+			p = math.Exp(-2 * ne * d * d)
+		}
+	}
+	return ksResult(d, p)
+}
+
+// ShapiroWilk tests normality for 3 <= n <= 5000 using Royston's (1995)
+// coefficients and p-value approximation (AS R94); other sample sizes give a
+// NaN statistic and p-value.
+func ShapiroWilk(x []float64) TestResult {
+	n := len(x)
+	if n < 3 || n > 5000 {
+		return normalityResult(math.NaN(), math.NaN())
+	}
+	sorted := append([]float64{}, x...)
+	sort.Float64s(sorted)
+	a := shapiroWilkCoefficients(n)
+	num := 0.0
+	for i := range a {
+		num += a[i] * (sorted[n-1-i] - sorted[i])
+	}
+	mean := Mean(sorted)
+	ss := 0.0
+	for _, v := range sorted {
+		ss += (v - mean) * (v - mean)
+	}
+	w := math.Min(1, num*num/ss)
+	return normalityResult(w, shapiroWilkPValue(w, n))
+}
+
+// AndersonDarling tests normality with estimated mean and standard deviation.
+// The statistic is A²; the p-value uses the D'Agostino–Stephens
+// approximation for the small-sample adjusted A²(1 + 0.75/n + 2.25/n²).
+func AndersonDarling(x []float64) TestResult {
+	sorted := append([]float64{}, x...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	nf := float64(n)
+	normal := Normal{Mu: Mean(sorted), Sigma: math.Sqrt(SampleVariance(sorted))}
+	s := 0.0
+	for i, v := range sorted {
+		s += float64(2*i+1) * (math.Log(normal.CDF(v)) + math.Log(normal.Survival(sorted[n-1-i])))
+	}
+	a2 := -nf - s/nf
+	aa := a2 * (1 + 0.75/nf + 2.25/(nf*nf))
+	var p float64
+	switch {
+	case aa < 0.2:
+		p = 1 - math.Exp(-13.436+101.14*aa-223.73*aa*aa)
+	case aa < 0.34:
+		p = 1 - math.Exp(-8.318+42.796*aa-59.938*aa*aa)
+	case aa < 0.6:
+		p = math.Exp(0.9177 - 4.279*aa - 1.38*aa*aa)
+	default:
+		p = math.Exp(1.2937 - 5.709*aa + 0.0186*aa*aa)
+	}
+	return normalityResult(a2, math.Min(1, math.Max(0, p)))
+}
+
+// SpearmanTest returns Spearman's ρ (Estimate and EffectSize) with the
+// statistic S = Σd² of the ranks. Small untied samples use the exact
+// permutation distribution, others the t approximation with n-2 df.
+func SpearmanTest(x, y []float64, alt Alternative) TestResult {
+	rx, tx := averageRanks(x)
+	ry, ty := averageRanks(y)
+	n := len(x)
+	nf := float64(n)
+	rho := pearson(rx, ry)
+	s := 0.0
+	for i := range rx {
+		s += (rx[i] - ry[i]) * (rx[i] - ry[i])
+	}
+	var p float64
+	if len(tx) == 0 && len(ty) == 0 && n <= exactSpearmanLimit {
+		// Positive association means a small S.
+		flipped := map[Alternative]Alternative{TwoSided: TwoSided, Less: Greater, Greater: Less}[alt]
+		p = exactPValue(spearmanCounts(n), int(math.Round(s)), flipped)
+	} else {
+		t := rho * math.Sqrt((nf-2)/(1-rho*rho))
+		p = pValue(StudentT{Nu: nf - 2}, t, alt)
+	}
+	return correlationResult(s, p, rho)
+}
+
+// KendallTest returns Kendall's τ_b (Estimate and EffectSize). Small untied
+// samples report the concordant-pair count T with its exact distribution;
+// otherwise the statistic is the tie-corrected z score.
+func KendallTest(x, y []float64, alt Alternative) TestResult {
+	n := len(x)
+	concordant, discordant := 0, 0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			s := (x[i] - x[j]) * (y[i] - y[j])
+			if s > 0 {
+				concordant++
+			} else if s < 0 {
+				discordant++
+			}
+		}
+	}
+	_, tx := averageRanks(x)
+	_, ty := averageRanks(y)
+	nf := float64(n)
+	n0 := nf * (nf - 1) / 2
+	n1, n2 := 0.0, 0.0
+	for _, t := range tx {
+		n1 += t * (t - 1) / 2
+	}
+	for _, u := range ty {
+		n2 += u * (u - 1) / 2
+	}
+	s := float64(concordant - discordant)
+	tau := s / math.Sqrt((n0-n1)*(n0-n2))
+	if len(tx) == 0 && len(ty) == 0 && n < exactRankLimit {
+		return correlationResult(float64(concordant), exactPValue(kendallCounts(n), concordant, alt), tau)
+	}
+	v0 := nf * (nf - 1) * (2*nf + 5)
+	vt, vu, t1, u1, t2, u2 := 0.0, 0.0, 0.0, 0.0, 0.0, 0.0
+	for _, t := range tx {
+		vt += t * (t - 1) * (2*t + 5)
+		t1 += t * (t - 1)
+		t2 += t * (t - 1) * (t - 2)
+	}
+	for _, u := range ty {
+		vu += u * (u - 1) * (2*u + 5)
+		u1 += u * (u - 1)
+		u2 += u * (u - 1) * (u - 2)
+	}
+	variance := (v0-vt-vu)/18 + t1*u1/(2*nf*(nf-1)) + t2*u2/(9*nf*(nf-1)*(nf-2))
+	z := s / math.Sqrt(variance)
+	return correlationResult(z, pValue(Normal{Mu: 0, Sigma: 1}, z, alt), tau)
+}
+
+func rankResult(stat, p, effect float64) TestResult {
+	return TestResult{
+		Statistic:  stat,
+		PValue:     p,
+		Estimate:   math.NaN(),
+		CILower:    math.NaN(),
+		CIUpper:    math.NaN(),
+		EffectSize: effect,
+	}
+}
+
+func ksResult(d, p float64) TestResult {
+	return normalityResult(d, math.Min(1, math.Max(0, p)))
+}
+
+func normalityResult(stat, p float64) TestResult {
+	return rankResult(stat, p, math.NaN())
+}
+
+func correlationResult(stat, p, coefficient float64) TestResult {
+	return TestResult{
+		Statistic:  stat,
+		PValue:     p,
+		Estimate:   coefficient,
+		CILower:    math.NaN(),
+		CIUpper:    math.NaN(),
+		EffectSize: coefficient,
+	}
+}
+
+// averageRanks returns 1-based ranks with ties given their average rank, and
+// the size of every tied group.
+func averageRanks(values []float64) ([]float64, []float64) {
+	n := len(values)
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+	ranks := make([]float64, n)
+	ties := []float64{}
+	for i := 0; i < n; {
+		j := i
+		for j+1 < n && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		for k := i; k <= j; k++ {
+			ranks[idx[k]] = float64(i+j)/2 + 1
+		}
+		if j > i {
+			ties = append(ties, float64(j-i+1))
+		}
+		i = j + 1
+	}
+	return ranks, ties
+}
+
+func tieSum(ties []float64) float64 {
+	s := 0.0
+	for _, t := range ties {
+		s += t*t*t - t
+	}
+	return s
+}
+
+func hasTies(sorted []float64) bool {
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return true
+		}
+	}
+	return false
+}
+
+func pearson(x, y []float64) float64 {
+	mx, my := Mean(x), Mean(y)
+	sxy, sxx, syy := 0.0, 0.0, 0.0
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+		syy += (y[i] - my) * (y[i] - my)
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// normalApproxPValue applies R's continuity correction of one half towards
+// the mean before standardising.
+func normalApproxPValue(stat, mean, sd float64, alt Alternative) float64 {
+	diff := stat - mean
+	switch alt {
+	case TwoSided:
+		diff -= math.Copysign(math.Min(0.5, math.Abs(diff)), diff)
+	case Greater:
+		diff -= 0.5
+	case Less:
+		diff += 0.5
+	}
+	return pValue(Normal{Mu: 0, Sigma: 1}, diff/sd, alt)
+}
+
+// exactPValue returns the p-value of the observed value k of an integer
+// statistic whose null frequencies are counts[0], counts[1], ...
+func exactPValue(counts []float64, k int, alt Alternative) float64 {
+	total, lower, upper := 0.0, 0.0, 0.0
+	for i, c := range counts {
+		total += c
+		if i <= k {
+			lower += c
+		}
+		if i >= k {
+			upper += c
+		}
+	}
+	switch alt {
+	case Less:
+		return lower / total
+	case Greater:
+		return upper / total
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// mannWhitneyCounts returns the number of orderings of m x's and n y's giving
+// each value of U, from f(u; i, j) = f(u-j; i-1, j) + f(u; i, j-1).
+func mannWhitneyCounts(m, n int) []float64 {
+	prev := make([][]float64, n+1)
+	for j := range prev {
+		prev[j] = []float64{1}
+	}
+	for i := 1; i <= m; i++ {
+		cur := make([][]float64, n+1)
+		cur[0] = []float64{1}
+		for j := 1; j <= n; j++ {
+			cur[j] = make([]float64, i*j+1)
+			copy(cur[j], cur[j-1])
+			for u, c := range prev[j] {
+				cur[j][u+j] += c
+			}
+		}
+		prev = cur
+	}
+	return prev[n]
+}
+
+func signedRankCounts(n int) []float64 {
+	counts := make([]float64, n*(n+1)/2+1)
+	counts[0] = 1
+	for k := 1; k <= n; k++ {
+		for s := k * (k + 1) / 2; s >= k; s-- {
+			counts[s] += counts[s-k]
+		}
+	}
+	return counts
+}
+
+// kendallCounts returns the number of permutations of n items with each
+// count of concordant pairs (the Mahonian numbers).
+func kendallCounts(n int) []float64 {
+	counts := []float64{1}
+	for k := 2; k <= n; k++ {
+		next := make([]float64, len(counts)+k-1)
+		for i, c := range counts {
+			for j := 0; j < k; j++ {
+				next[i+j] += c
+			}
+		}
+		counts = next
+	}
+	return counts
+}
+
+// spearmanCounts enumerates the n! rank permutations by S = Σd².
+func spearmanCounts(n int) []float64 {
+	counts := make([]float64, n*(n*n-1)/3+1)
+	perm := make([]int, n)
+	used := make([]bool, n)
+	var visit func(pos, s int)
+	visit = func(pos, s int) {
+		if pos == n {
+			counts[s]++
+			return
+		}
+		for v := 0; v < n; v++ {
+			if !used[v] {
+				used[v] = true
+				perm[pos] = v
+				visit(pos+1, s+(pos-v)*(pos-v))
+				used[v] = false
+			}
+		}
+	}
+	visit(0, 0)
+	return counts
+}
+
+func kruskalH(sums []float64, sizes []int, n float64) float64 {
+	h := 0.0
+	for i, s := range sums {
+		h += s * s / float64(sizes[i])
+	}
+	return 12/(n*(n+1))*h - 3*(n+1)
+}
+
+func multinomialCount(sizes []int) float64 {
+	total := 0
+	l := 0.0
+	for _, s := range sizes {
+		total += s
+		l -= lgamma(float64(s + 1))
+	}
+	return math.Exp(l + lgamma(float64(total+1)))
+}
+
+// kruskalWallisExact enumerates every assignment of the ranks 1..N to groups
+// of the given sizes and returns the share with H at least the observed one.
+func kruskalWallisExact(sizes []int, observed float64) float64 {
+	n := 0
+	for _, s := range sizes {
+		n += s
+	}
+	remaining := append([]int{}, sizes...)
+	sums := make([]float64, len(sizes))
+	extreme, total := 0.0, 0.0
+	var assign func(rank int)
+	assign = func(rank int) {
+		if rank > n {
+			total++
+			if kruskalH(sums, sizes, float64(n)) >= observed-1e-9 {
+				extreme++
+			}
+			return
+		}
+		for g := range remaining {
+			if remaining[g] > 0 {
+				remaining[g]--
+				sums[g] += float64(rank)
+				assign(rank + 1)
+				sums[g] -= float64(rank)
+				remaining[g]++
+			}
+		}
+	}
+	assign(1)
+	return extreme / total
+}
+
+func ksStatistic(dPlus, dMinus float64, alt Alternative) float64 {
+	switch alt {
+	case Greater:
+		return dPlus
+	case Less:
+		return dMinus
+	}
+	return math.Max(dPlus, dMinus)
+}
+
+// kolmogorovSurvival returns P(K > t) for the limiting Kolmogorov
+// distribution, using the theta-function form for small t.
+func kolmogorovSurvival(t float64) float64 {
+	if t <= 0 {
+		return 1
+	}
+	if t < 1 {
+		s := 0.0
+		for k := 1; k <= 20; k++ {
+			m := float64(2*k - 1)
+			s += math.Exp(-m * m * math.Pi * math.Pi / (8 * t * t))
+		}
+		return 1 - math.Sqrt(2*math.Pi)/t*s
+	}
+	s, sign := 0.0, 1.0
+	for k := 1; k <= 100; k++ {
+		term := math.Exp(-2 * float64(k*k) * t * t)
+		s += sign * term
+		if term < 1e-18 {
+			break
+		}
+		sign = -sign
+	}
+	return 2 * s
+}
+
+// kolmogorovExactCDF returns P(D_n < d) by the matrix method of Marsaglia,
+// Tsang and Wang (2003), tracking a decimal exponent to avoid overflow.
+func kolmogorovExactCDF(n int, d float64) float64 {
+	nd := float64(n) * d
+	k := int(nd) + 1
+	m := 2*k - 1
+	h := float64(k) - nd
+	H := make([][]float64, m)
+	for i := range H {
+		H[i] = make([]float64, m)
+		for j := range H[i] {
+			if i-j+1 >= 0 {
+				H[i][j] = 1
+			}
+		}
+	}
+	for i := 0; i < m; i++ {
+		H[i][0] -= math.Pow(h, float64(i+1))
+		H[m-1][i] -= math.Pow(h, float64(m-i))
+	}
+	if 2*h-1 > 0 {
+		H[m-1][0] += math.Pow(2*h-1, float64(m))
+	}
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			for g := 1; g <= i-j+1; g++ {
+				H[i][j] /= float64(g)
+			}
+		}
+	}
+	Q, e := kolmogorovMatrixPower(H, n)
+	s := Q[k-1][k-1]
+	for i := 1; i <= n; i++ {
+		s *= float64(i) / float64(n)
+		if s < 1e-140 {
+			s *= 1e140
+			e -= 140
+		}
+	}
+	return s * math.Pow(10, float64(e))
+}
+
+func kolmogorovMatrixPower(A [][]float64, n int) ([][]float64, int) {
+	if n == 1 {
+		return A, 0
+	}
+	V, e := kolmogorovMatrixPower(A, n/2)
+	B := multiply(V, V)
+	e *= 2
+	if n%2 == 1 {
+		B = multiply(A, B)
+	}
+	c := len(B) / 2
+	if B[c][c] > 1e140 {
+		for i := range B {
+			for j := range B[i] {
+				B[i][j] *= 1e-140
+			}
+		}
+		e += 140
+	}
+	return B, e
+}
+
+// smirnovOneSided returns P(D+_n >= d) by the Birnbaum–Tingey formula.
+func smirnovOneSided(n int, d float64) float64 {
+	if d <= 0 {
+		return 1
+	}
+	nf := float64(n)
+	s := 0.0
+	for j := 0; j <= int(math.Floor(nf*(1-d))); j++ {
+		jf := float64(j)
+		s += math.Exp(lchoose(n, j) + (nf-jf)*math.Log(1-d-jf/nf) + (jf-1)*math.Log(d+jf/nf))
+	}
+	return d * s
+}
+
+// smirnovExact returns P(D >= d) for the two-sample statistic by counting
+// lattice paths that stay inside the band, normalised as they are built.
+func smirnovExact(m, n int, d float64, alt Alternative) float64 {
+	md, nd := float64(m), float64(n)
+	q := (0.5 + math.Floor(d*md*nd-1e-7)) / (md * nd)
+	outside := func(i, j int) bool {
+		diff := float64(i)/md - float64(j)/nd
+		switch alt {
+		case Greater:
+			return diff > q
+		case Less:
+			return -diff > q
+		}
+		return math.Abs(diff) > q
+	}
+	u := make([]float64, n+1)
+	for j := range u {
+		if !outside(0, j) {
+			u[j] = 1
+		}
+	}
+	for i := 1; i <= m; i++ {
+		w := float64(i) / float64(i+n)
+		if outside(i, 0) {
+			u[0] = 0
+		} else {
+			u[0] *= w
+		}
+		for j := 1; j <= n; j++ {
+			if outside(i, j) {
+				u[j] = 0
+			} else {
+				u[j] = w*u[j] + u[j-1]
+			}
+		}
+	}
+	return 1 - u[n]
+}
+
+func shapiroWilkCoefficients(n int) []float64 {
+	half := n / 2
+	a := make([]float64, half)
+	if n == 3 {
+		a[0] = math.Sqrt(0.5)
+		return a
+	}
+	m := make([]float64, half)
+	summ2 := 0.0
+	for i := range m {
+		m[i] = StandardNormalQuantile((float64(i+1) - 0.375) / (float64(n) + 0.25))
+		summ2 += m[i] * m[i]
+	}
+	summ2 *= 2
+	ssumm2 := math.Sqrt(summ2)
+	rsn := 1 / math.Sqrt(float64(n))
+	a1 := polynomial([]float64{0, 0.221157, -0.147981, -2.07119, 4.434685, -2.706056}, rsn) - m[0]/ssumm2
+	first := 1
+	var fac float64
+	if n > 5 {
+		first = 2
+		a2 := -m[1]/ssumm2 + polynomial([]float64{0, 0.042981, -0.293762, -1.752461, 5.682633, -3.582633}, rsn)
+		fac = math.Sqrt((summ2 - 2*m[0]*m[0] - 2*m[1]*m[1]) / (1 - 2*a1*a1 - 2*a2*a2))
+		a[1] = a2
+	} else {
+		fac = math.Sqrt((summ2 - 2*m[0]*m[0]) / (1 - 2*a1*a1))
+	}
+	a[0] = a1
+	for i := first; i < half; i++ {
+		a[i] = -m[i] / fac
+	}
+	return a
+}
+
+func shapiroWilkPValue(w float64, n int) float64 {
+	if n == 3 {
+		return math.Max(0, 6/math.Pi*(math.Asin(math.Sqrt(w))-math.Pi/3))
+	}
+	nf := float64(n)
+	y := math.Log(1 - w)
+	var mean, sd float64
+	if n <= 11 {
+		gamma := -2.273 + 0.459*nf
+		if y >= gamma {
+			return 1e-99
+		}
+		y = -math.Log(gamma - y)
+		mean = polynomial([]float64{0.544, -0.39978, 0.025054, -6.714e-4}, nf)
+		sd = math.Exp(polynomial([]float64{1.3822, -0.77857, 0.062767, -0.0020322}, nf))
+	} else {
+		ln := math.Log(nf)
+		mean = polynomial([]float64{-1.5861, -0.31082, -0.083751, 0.0038915}, ln)
+		sd = math.Exp(polynomial([]float64{-0.4803, -0.082676, 0.0030302}, ln))
+	}
+	return Normal{Mu: mean, Sigma: sd}.Survival(y)
+}
+
+// polynomial evaluates c[0] + c[1]x + c[2]x² + ...
+func polynomial(c []float64, x float64) float64 {
+	s := 0.0
+	for i := len(c) - 1; i >= 0; i-- {
+		s = s*x + c[i]
+	}
+	return s
+}
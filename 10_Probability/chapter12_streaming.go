@@ -0,0 +1,183 @@
+// 2026 Update: Streaming Statistics
+package probability
+
+import "math"
+
+// RunningStats accumulates moments in one pass using Welford's update
+// extended to the third and fourth moments (Pébay, 2008). The zero value is
+// ready to use, and partial results from separate goroutines combine exactly
+// with Merge. Variance, Skewness and Kurtosis follow the population
+// conventions of the batch functions.
+type RunningStats struct {
+	n              int
+	mean           float64
+	m2, m3, m4     float64
+	minVal, maxVal float64
+}
+
+func (s *RunningStats) Add(x float64) {
+	n1 := float64(s.n)
+	s.n++
+	n := float64(s.n)
+	delta := x - s.mean
+	dn := delta / n
+	dn2 := dn * dn
+	term := delta * dn * n1
+	s.mean += dn
+	s.m4 += term*dn2*(n*n-3*n+3) + 6*dn2*s.m2 - 4*dn*s.m3
+	s.m3 += term*dn*(n-2) - 3*dn*s.m2
+	s.m2 += term
+	if s.n == 1 || x < s.minVal {
+		s.minVal = x
+	}
+	if s.n == 1 || x > s.maxVal {
+		s.maxVal = x
+	}
+}
+
+// Merge folds o into s as if every observation of o had been added to s.
+func (s *RunningStats) Merge(o RunningStats) {
+	if o.n == 0 {
+		return
+	}
+	if s.n == 0 {
+		*s = o
+		return
+	}
+	na, nb := float64(s.n), float64(o.n)
+	n := na + nb
+	delta := o.mean - s.mean
+	d2 := delta * delta
+	m2 := s.m2 + o.m2 + d2*na*nb/n
+	m3 := s.m3 + o.m3 + d2*delta*na*nb*(na-nb)/(n*n) + 3*delta*(na*o.m2-nb*s.m2)/n
+	m4 := s.m4 + o.m4 + d2*d2*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*d2*(na*na*o.m2+nb*nb*s.m2)/(n*n) + 4*delta*(na*o.m3-nb*s.m3)/n
+	s.mean += delta * nb / n
+	s.m2, s.m3, s.m4 = m2, m3, m4
+	s.n += o.n
+	s.minVal = math.Min(s.minVal, o.minVal)
+	s.maxVal = math.Max(s.maxVal, o.maxVal)
+}
+
+func (s *RunningStats) Count() int {
+	return s.n
+}
+
+func (s *RunningStats) Mean() float64 {
+	return s.mean
+}
+
+func (s *RunningStats) Variance() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return s.m2 / float64(s.n)
+}
+
+func (s *RunningStats) SampleVariance() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+func (s *RunningStats) StandardDeviation() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+func (s *RunningStats) Skewness() float64 {
+	if s.n < 3 || s.m2 == 0 {
+		return 0
+	}
+	return math.Sqrt(float64(s.n)) * s.m3 / math.Pow(s.m2, 1.5)
+}
+
+// Kurtosis returns the excess kurtosis.
+func (s *RunningStats) Kurtosis() float64 {
+	if s.n < 4 || s.m2 == 0 {
+		return 0
+	}
+	return float64(s.n)*s.m4/(s.m2*s.m2) - 3
+}
+
+func (s *RunningStats) Min() float64 {
+	return s.minVal
+}
+
+func (s *RunningStats) Max() float64 {
+	return s.maxVal
+}
+
+// RunningCovariance accumulates the co-moment of paired observations in one
+// pass. Like RunningStats, the zero value is ready and Merge is exact.
+type RunningCovariance struct {
+	n            int
+	meanX, meanY float64
+	m2x, m2y     float64
+	cxy          float64
+}
+
+func (c *RunningCovariance) Add(x, y float64) {
+	c.n++
+	n := float64(c.n)
+	dx := x - c.meanX
+	dy := y - c.meanY
+	c.meanX += dx / n
+	c.meanY += dy / n
+	c.m2x += dx * (x - c.meanX)
+	c.m2y += dy * (y - c.meanY)
+	c.cxy += dx * (y - c.meanY)
+}
+
+func (c *RunningCovariance) Merge(o RunningCovariance) {
+	if o.n == 0 {
+		return
+	}
+	if c.n == 0 {
+		*c = o
+		return
+	}
+	na, nb := float64(c.n), float64(o.n)
+	n := na + nb
+	dx := o.meanX - c.meanX
+	dy := o.meanY - c.meanY
+	c.m2x += o.m2x + dx*dx*na*nb/n
+	c.m2y += o.m2y + dy*dy*na*nb/n
+	c.cxy += o.cxy + dx*dy*na*nb/n
+	c.meanX += dx * nb / n
+	c.meanY += dy * nb / n
+	c.n += o.n
+}
+
+func (c *RunningCovariance) Count() int {
+	return c.n
+}
+
+func (c *RunningCovariance) MeanX() float64 {
+	return c.meanX
+}
+
+func (c *RunningCovariance) MeanY() float64 {
+	return c.meanY
+}
+
+func (c *RunningCovariance) Covariance() float64 {
+	if c.n == 0 {
+		return 0
+	}
+	return c.cxy / float64(c.n)
+}
+
+func (c *RunningCovariance) SampleCovariance() float64 {
+	if c.n < 2 {
+		return 0
+	}
+	return c.cxy / float64(c.n-1)
+}
+
+func (c *RunningCovariance) Correlation() float64 {
+	if c.m2x == 0 || c.m2y == 0 {
+		return 0
+	}
+	return c.cxy / math.Sqrt(c.m2x*c.m2y)
+}
@@ -0,0 +1,245 @@
+// 2026 Update: Quantile Sketches
+package probability
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TDigest is a merging t-digest (Dunning & Ertl, 2019) for streaming
+// quantiles. Points are buffered and periodically merged into weighted
+// centroids whose size is limited by the arcsine scale function
+// k(q) = δ/(2π)·asin(2q-1). Any two adjacent centroids span more than one
+// unit of k, so at most δ+2 centroids and a 5δ buffer are kept regardless of
+// the stream length.
+//
+// Error bounds: every centroid spans at most 2π·√(q(1-q))/δ of the total
+// rank around its quantile q, and Quantile and CDF interpolate between
+// neighbouring centroids, so the rank error at q is of the order of that span
+// (about 0.031 at the median and 0.0044 at the 0.5th percentile for δ = 100)
+// and typically several times smaller. The extremes are exact.
+// Merging digests re-applies the same limit, so the bound holds after Merge.
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	minVal      float64
+	maxVal      float64
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewTDigest returns an empty digest; compression δ trades memory for
+// accuracy, and 100 is a common choice.
+func NewTDigest(compression float64) *TDigest {
+	return &TDigest{
+		compression: compression,
+		buffer:      make([]centroid, 0, tdigestBufferSize(compression)),
+		minVal:      math.Inf(1),
+		maxVal:      math.Inf(-1),
+	}
+}
+
+func tdigestBufferSize(compression float64) int {
+	return int(5*compression) + 10
+}
+
+func (t *TDigest) Add(x float64) {
+	t.buffer = append(t.buffer, centroid{x, 1})
+	t.count++
+	t.minVal = math.Min(t.minVal, x)
+	t.maxVal = math.Max(t.maxVal, x)
+	if len(t.buffer) == cap(t.buffer) {
+		t.compress()
+	}
+}
+
+// Merge adds the contents of o to t, leaving o unchanged.
+func (t *TDigest) Merge(o *TDigest) {
+	if o.count == 0 {
+		return
+	}
+	t.buffer = append(t.buffer, o.centroids...)
+	t.buffer = append(t.buffer, o.buffer...)
+	t.count += o.count
+	t.minVal = math.Min(t.minVal, o.minVal)
+	t.maxVal = math.Max(t.maxVal, o.maxVal)
+	t.compress()
+}
+
+func (t *TDigest) Count() int {
+	return int(t.count)
+}
+
+// Size returns the number of centroids retained after compression.
+func (t *TDigest) Size() int {
+	t.compress()
+	return len(t.centroids)
+}
+
+func (t *TDigest) Min() float64 {
+	return t.minVal
+}
+
+func (t *TDigest) Max() float64 {
+	return t.maxVal
+}
+
+// Quantile returns an estimate of the p-quantile, or NaN for an empty digest.
+func (t *TDigest) Quantile(p float64) float64 {
+	t.compress()
+	c := t.centroids
+	switch {
+	case len(c) == 0 || p < 0 || p > 1:
+		return math.NaN()
+	case p == 0:
+		return t.minVal
+	case p == 1:
+		return t.maxVal
+	case len(c) == 1:
+		return t.minVal + p*(t.maxVal-t.minVal)
+	}
+	index := p * t.count
+	if index < c[0].weight/2 {
+		return t.minVal + index/(c[0].weight/2)*(c[0].mean-t.minVal)
+	}
+	cumulative := c[0].weight / 2
+	for i := 0; i+1 < len(c); i++ {
+		dw := (c[i].weight + c[i+1].weight) / 2
+		if cumulative+dw > index {
+			z := (index - cumulative) / dw
+			return c[i].mean + z*(c[i+1].mean-c[i].mean)
+		}
+		cumulative += dw
+	}
+	last := c[len(c)-1]
+	z := (index - cumulative) / (last.weight / 2)
+	return last.mean + math.Min(1, z)*(t.maxVal-last.mean)
+}
+
+// CDF returns an estimate of the fraction of observations at or below x; it
+// is the inverse of Quantile between the extremes.
+func (t *TDigest) CDF(x float64) float64 {
+	t.compress()
+	c := t.centroids
+	switch {
+	case len(c) == 0:
+		return math.NaN()
+	case x < t.minVal:
+		return 0
+	case x >= t.maxVal:
+		return 1
+	case len(c) == 1:
+		return (x - t.minVal) / (t.maxVal - t.minVal)
+	}
+	if x < c[0].mean {
+		return c[0].weight / 2 * (x - t.minVal) / (c[0].mean - t.minVal) / t.count
+	}
+	cumulative := c[0].weight / 2
+	for i := 0; i+1 < len(c); i++ {
+		dw := (c[i].weight + c[i+1].weight) / 2
+		if x < c[i+1].mean {
+			return (cumulative + dw*(x-c[i].mean)/(c[i+1].mean-c[i].mean)) / t.count
+		}
+		cumulative += dw
+	}
+	last := c[len(c)-1]
+	return (cumulative + last.weight/2*(x-last.mean)/(t.maxVal-last.mean)) / t.count
+}
+
+// compress merges the buffer into the centroids in one sorted pass, joining
+// neighbours while the combined centroid spans at most one unit of k.
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.buffer, t.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+	merged := make([]centroid, 0, len(t.centroids)+1)
+	cur := all[0]
+	before := 0.0
+	for _, next := range all[1:] {
+		if t.scale((before+cur.weight+next.weight)/t.count)-t.scale(before/t.count) <= 1 {
+			cur.weight += next.weight
+			cur.mean += (next.mean - cur.mean) * next.weight / cur.weight
+		} else {
+			merged = append(merged, cur)
+			before += cur.weight
+			cur = next
+		}
+	}
+	t.centroids = append(merged, cur)
+	t.buffer = make([]centroid, 0, tdigestBufferSize(t.compression))
+}
+
+func (t *TDigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*math.Min(1, q)-1)
+}
+
+const (
+	tdigestEncodingVersion = 1
+	// tdigestMaxCompression bounds the compression accepted when decoding,
+	// since it sizes the buffer that is allocated.
+	tdigestMaxCompression = 1e6
+)
+
+// MarshalBinary encodes the compressed digest as little-endian values: a
+// version byte, compression, count, min, max, the centroid count and then
+// each centroid's mean and weight.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+	out := make([]byte, 1, 1+8*5+16*len(t.centroids))
+	out[0] = tdigestEncodingVersion
+	for _, v := range []float64{t.compression, t.count, t.minVal, t.maxVal} {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(v))
+	}
+	out = binary.LittleEndian.AppendUint64(out, uint64(len(t.centroids)))
+	for _, c := range t.centroids {
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(c.mean))
+		out = binary.LittleEndian.AppendUint64(out, math.Float64bits(c.weight))
+	}
+	return out, nil
+}
+
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < 41 {
+		return fmt.Errorf("tdigest: encoding too short (%d bytes)", len(data))
+	}
+	if data[0] != tdigestEncodingVersion {
+		return fmt.Errorf("tdigest: unsupported encoding version %d", data[0])
+	}
+	read := func(i int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(data[1+8*i:]))
+	}
+	// Compare n with the bytes available before multiplying, so a corrupt
+	// count cannot overflow the length check.
+	n := binary.LittleEndian.Uint64(data[33:])
+	if n > uint64(len(data)-41)/16 || uint64(len(data)-41) != 16*n {
+		return fmt.Errorf("tdigest: expected %d centroids, found %d bytes", n, len(data)-41)
+	}
+	compression, count := read(0), read(1)
+	if !(compression > 0 && compression <= tdigestMaxCompression) {
+		return fmt.Errorf("tdigest: invalid compression %g", compression)
+	}
+	if !(count >= 0) || math.IsInf(count, 1) {
+		return fmt.Errorf("tdigest: invalid count %g", count)
+	}
+	centroids := make([]centroid, n)
+	for i := range centroids {
+		c := centroid{read(5 + 2*i), read(6 + 2*i)}
+		if math.IsNaN(c.mean) || !(c.weight > 0) || math.IsInf(c.weight, 1) {
+			return fmt.Errorf("tdigest: invalid centroid %d (mean %g, weight %g)", i, c.mean, c.weight)
+		}
+		centroids[i] = c
+	}
+	t.compression, t.count, t.minVal, t.maxVal = compression, count, read(2), read(3)
+	t.centroids = centroids
+	t.buffer = make([]centroid, 0, tdigestBufferSize(t.compression))
+	return nil
+}
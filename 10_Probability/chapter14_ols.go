@@ -0,0 +1,272 @@
+// 2026 Update: Ordinary Least Squares
+package probability
+
+import (
+	"fmt"
+	"math"
+)
+
+// OLSResult reports a least-squares fit. Coefficient slices start with the
+// intercept when one was requested; VIF has one entry per predictor column
+// of X. With an intercept, R² and F compare against the mean-only model,
+// otherwise against the zero model, as R's lm does.
+type OLSResult struct {
+	Coefficients     []float64
+	StdErrors        []float64
+	TStats           []float64
+	PValues          []float64
+	CILower          []float64
+	CIUpper          []float64
+	RSquared         float64
+	AdjRSquared      float64
+	FStatistic       float64
+	FPValue          float64
+	DFModel          float64
+	DFResidual       float64
+	ResidualStdError float64
+	Fitted           []float64
+	Residuals        []float64
+	Leverage         []float64
+	CooksDistance    []float64
+	VIF              []float64
+}
+
+// OLS regresses y on the columns of X (one row per observation) through a
+// Householder QR factorisation, so the normal equations are never formed.
+func OLS(X [][]float64, y []float64, intercept bool, confidence float64) (OLSResult, error) {
+	A := designMatrix(X, intercept)
+	n, p := len(A), len(A[0])
+	if n <= p {
+		return OLSResult{}, fmt.Errorf("ols: %d observations for %d coefficients", n, p)
+	}
+	qr, err := householderQR(A)
+	if err != nil {
+		return OLSResult{}, err
+	}
+	beta := qr.solve(y)
+	res := OLSResult{
+		Coefficients:  beta,
+		Fitted:        make([]float64, n),
+		Residuals:     make([]float64, n),
+		Leverage:      make([]float64, n),
+		CooksDistance: make([]float64, n),
+		DFResidual:    float64(n - p),
+		DFModel:       float64(p),
+	}
+	if intercept {
+		res.DFModel--
+	}
+	sse := 0.0
+	for i, row := range A {
+		for j, v := range row {
+			res.Fitted[i] += v * beta[j]
+		}
+		res.Residuals[i] = y[i] - res.Fitted[i]
+		sse += res.Residuals[i] * res.Residuals[i]
+	}
+	s2 := sse / res.DFResidual
+	res.ResidualStdError = math.Sqrt(s2)
+
+	// (XᵀX)⁻¹ = R⁻¹R⁻ᵀ, so standard errors are row norms of R⁻¹ and leverage
+	// is ‖R⁻ᵀxᵢ‖².
+	rInv := qr.inverseR()
+	t := StudentT{Nu: res.DFResidual}
+	crit := t.Quantile(1 - (1-confidence)/2)
+	for j := range beta {
+		v := 0.0
+		for k := j; k < p; k++ {
+			v += rInv[j][k] * rInv[j][k]
+		}
+		se := math.Sqrt(v * s2)
+		stat := beta[j] / se
+		res.StdErrors = append(res.StdErrors, se)
+		res.TStats = append(res.TStats, stat)
+		res.PValues = append(res.PValues, pValue(t, stat, TwoSided))
+		res.CILower = append(res.CILower, beta[j]-crit*se)
+		res.CIUpper = append(res.CIUpper, beta[j]+crit*se)
+	}
+	for i, row := range A {
+		h := 0.0
+		for k := 0; k < p; k++ {
+			z := 0.0
+			for j := 0; j <= k; j++ {
+				z += rInv[j][k] * row[j]
+			}
+			h += z * z
+		}
+		res.Leverage[i] = h
+		e := res.Residuals[i]
+		res.CooksDistance[i] = e * e / (float64(p) * s2) * h / ((1 - h) * (1 - h))
+	}
+
+	sst := totalSumOfSquares(y, intercept)
+	res.RSquared = 1 - sse/sst
+	res.AdjRSquared = 1 - (1-res.RSquared)*(res.DFModel+res.DFResidual)/res.DFResidual
+	res.FStatistic = (sst - sse) / res.DFModel / s2
+	res.FPValue = FisherF{D1: res.DFModel, D2: res.DFResidual}.Survival(res.FStatistic)
+	res.VIF = varianceInflation(X, intercept)
+	return res, nil
+}
+
+func designMatrix(X [][]float64, intercept bool) [][]float64 {
+	A := make([][]float64, len(X))
+	for i, row := range X {
+		if intercept {
+			A[i] = append([]float64{1}, row...)
+		} else {
+			A[i] = append([]float64{}, row...)
+		}
+	}
+	return A
+}
+
+func totalSumOfSquares(y []float64, centred bool) float64 {
+	m := 0.0
+	if centred {
+		m = Mean(y)
+	}
+	s := 0.0
+	for _, v := range y {
+		s += (v - m) * (v - m)
+	}
+	return s
+}
+
+// varianceInflation regresses each column of X on the others, with the same
+// intercept choice, and returns 1/(1 - R²).
+func varianceInflation(X [][]float64, intercept bool) []float64 {
+	k := len(X[0])
+	vif := make([]float64, k)
+	for j := range vif {
+		if k == 1 && intercept {
+			vif[j] = 1
+			continue
+		}
+		others := make([][]float64, len(X))
+		target := make([]float64, len(X))
+		for i, row := range X {
+			target[i] = row[j]
+			others[i] = append(append([]float64{}, row[:j]...), row[j+1:]...)
+		}
+		A := designMatrix(others, intercept)
+		if len(A[0]) == 0 {
+			vif[j] = 1
+			continue
+		}
+		qr, err := householderQR(A)
+		if err != nil {
+			vif[j] = math.Inf(1)
+			continue
+		}
+		beta := qr.solve(target)
+		sse := 0.0
+		for i, row := range A {
+			fit := 0.0
+			for c, v := range row {
+				fit += v * beta[c]
+			}
+			sse += (target[i] - fit) * (target[i] - fit)
+		}
+		vif[j] = totalSumOfSquares(target, intercept) / sse
+	}
+	return vif
+}
+
+// qrFactor holds the Householder vectors v[k] (zero above row k) and the
+// upper-triangular R of A = QR.
+type qrFactor struct {
+	v  [][]float64
+	vv []float64
+	r  [][]float64
+}
+
+func householderQR(A [][]float64) (qrFactor, error) {
+	n, p := len(A), len(A[0])
+	a := make([][]float64, n)
+	columnNorms := make([]float64, p)
+	for i := range A {
+		a[i] = append([]float64{}, A[i]...)
+		for j, v := range A[i] {
+			columnNorms[j] = math.Hypot(columnNorms[j], v)
+		}
+	}
+	f := qrFactor{v: make([][]float64, p), vv: make([]float64, p), r: make([][]float64, p)}
+	for k := 0; k < p; k++ {
+		norm := 0.0
+		for i := k; i < n; i++ {
+			norm = math.Hypot(norm, a[i][k])
+		}
+		// A column that is (numerically) a combination of earlier ones has
+		// almost nothing left after the previous reflections.
+		if norm <= 1e-10*columnNorms[k] {
+			return qrFactor{}, fmt.Errorf("ols: design matrix is rank deficient at column %d", k)
+		}
+		v := make([]float64, n)
+		for i := k; i < n; i++ {
+			v[i] = a[i][k]
+		}
+		v[k] += math.Copysign(norm, a[k][k])
+		vv := 0.0
+		for i := k; i < n; i++ {
+			vv += v[i] * v[i]
+		}
+		for j := k; j < p; j++ {
+			s := 0.0
+			for i := k; i < n; i++ {
+				s += v[i] * a[i][j]
+			}
+			s *= 2 / vv
+			for i := k; i < n; i++ {
+				a[i][j] -= s * v[i]
+			}
+		}
+		f.v[k], f.vv[k] = v, vv
+	}
+	for i := range f.r {
+		f.r[i] = append([]float64{}, a[i][:p]...)
+	}
+	return f, nil
+}
+
+func (f qrFactor) solve(y []float64) []float64 {
+	p := len(f.r)
+	qty := append([]float64{}, y...)
+	for k, v := range f.v {
+		s := 0.0
+		for i := k; i < len(v); i++ {
+			s += v[i] * qty[i]
+		}
+		s *= 2 / f.vv[k]
+		for i := k; i < len(v); i++ {
+			qty[i] -= s * v[i]
+		}
+	}
+	beta := make([]float64, p)
+	for i := p - 1; i >= 0; i-- {
+		s := qty[i]
+		for j := i + 1; j < p; j++ {
+			s -= f.r[i][j] * beta[j]
+		}
+		beta[i] = s / f.r[i][i]
+	}
+	return beta
+}
+
+func (f qrFactor) inverseR() [][]float64 {
+	p := len(f.r)
+	inv := make([][]float64, p)
+	for i := range inv {
+		inv[i] = make([]float64, p)
+	}
+	for j := 0; j < p; j++ {
+		inv[j][j] = 1 / f.r[j][j]
+		for i := j - 1; i >= 0; i-- {
+			s := 0.0
+			for k := i + 1; k <= j; k++ {
+				s += f.r[i][k] * inv[k][j]
+			}
+			inv[i][j] = -s / f.r[i][i]
+		}
+	}
+	return inv
+}
@@ -0,0 +1,336 @@
+// 2026 Update: Resampling Methods
+package probability
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	random "github.com/mouaadid/MathsWithGolang/16_Random"
+)
+
+// BootstrapSettings controls Bootstrap and PermutationTest. Replicate b always
+// draws from the b-th 2^64-step stream of a PCG64 seeded with Seed, so results
+// depend only on Seed and Replicates, never on Workers or scheduling.
+type BootstrapSettings struct {
+	Replicates int
+	Confidence float64
+	Seed       uint64
+	Workers    int
+	// InnerReplicates is the number of second-level resamples used to
+	// estimate each replicate's standard error for the studentized interval;
+	// zero skips that interval.
+	InnerReplicates int
+}
+
+func DefaultBootstrapSettings() BootstrapSettings {
+	return BootstrapSettings{
+		Replicates:      2000,
+		Confidence:      0.95,
+		Seed:            42,
+		Workers:         runtime.GOMAXPROCS(0),
+		InnerReplicates: 50,
+	}
+}
+
+type Interval struct {
+	Lower, Upper float64
+}
+
+// BootstrapResult holds the statistic on the original data, the bootstrap
+// bias and standard error, the replicates and four confidence intervals.
+// Studentized is NaN when InnerReplicates is zero, and BCa is NaN when every
+// replicate falls on one side of the estimate.
+type BootstrapResult struct {
+	Estimate    float64
+	Bias        float64
+	StdError    float64
+	Replicates  []float64
+	Percentile  Interval
+	Basic       Interval
+	BCa         Interval
+	Studentized Interval
+}
+
+// Bootstrap resamples data with replacement. stat is called concurrently and
+// must not modify its argument.
+func Bootstrap(data []float64, stat func([]float64) float64, settings BootstrapSettings) BootstrapResult {
+	return BootstrapSamples([][]float64{data}, func(s [][]float64) float64 { return stat(s[0]) }, settings)
+}
+
+// BootstrapSamples resamples each of several independent samples separately
+// (a stratified bootstrap), for statistics such as a difference or ratio of
+// means. The BCa acceleration uses the jackknife over all observations.
+func BootstrapSamples(samples [][]float64, stat func([][]float64) float64, settings BootstrapSettings) BootstrapResult {
+	estimate := stat(samples)
+	B := settings.Replicates
+	reps := make([]float64, B)
+	tstats := make([]float64, B)
+	parallelFor(B, settings.Workers, func(b int) {
+		rng := seededStream(settings.Seed, b)
+		resampled := resample(rng, samples)
+		reps[b] = stat(resampled)
+		if settings.InnerReplicates > 0 {
+			inner := make([]float64, settings.InnerReplicates)
+			for i := range inner {
+				inner[i] = stat(resample(rng, resampled))
+			}
+			tstats[b] = (reps[b] - estimate) / sampleStdDev(inner)
+		}
+	})
+
+	res := BootstrapResult{
+		Estimate:   estimate,
+		Bias:       Mean(reps) - estimate,
+		StdError:   sampleStdDev(reps),
+		Replicates: reps,
+	}
+	alpha := (1 - settings.Confidence) / 2
+	sorted := append([]float64{}, reps...)
+	sort.Float64s(sorted)
+	res.Percentile = Interval{sortedQuantile(sorted, alpha), sortedQuantile(sorted, 1-alpha)}
+	res.Basic = Interval{2*estimate - res.Percentile.Upper, 2*estimate - res.Percentile.Lower}
+
+	// BCa (Efron, 1987): bias correction from the share of replicates below
+	// the estimate, counting ties as half, and acceleration from the
+	// skewness of the jackknife values.
+	below := 0.0
+	for _, r := range reps {
+		if r < estimate {
+			below++
+		} else if r == estimate {
+			below += 0.5
+		}
+	}
+	std := Normal{Mu: 0, Sigma: 1}
+	z0 := std.Quantile(below / float64(B))
+	jack := jackknifeValues(samples, stat)
+	jm := Mean(jack)
+	num, den := 0.0, 0.0
+	for _, v := range jack {
+		d := jm - v
+		num += d * d * d
+		den += d * d
+	}
+	accel := 0.0
+	if den > 0 {
+		accel = num / (6 * math.Pow(den, 1.5))
+	}
+	adjust := func(p float64) float64 {
+		z := z0 + std.Quantile(p)
+		return std.CDF(z0 + z/(1-accel*z))
+	}
+	res.BCa = Interval{sortedQuantile(sorted, adjust(alpha)), sortedQuantile(sorted, adjust(1-alpha))}
+
+	res.Studentized = Interval{math.NaN(), math.NaN()}
+	if settings.InnerReplicates > 0 {
+		sort.Float64s(tstats)
+		res.Studentized = Interval{
+			estimate - sortedQuantile(tstats, 1-alpha)*res.StdError,
+			estimate - sortedQuantile(tstats, alpha)*res.StdError,
+		}
+	}
+	return res
+}
+
+// JackknifeResult holds the leave-one-out values and the jackknife estimates
+// of bias and variance; Estimate - Bias is the bias-corrected statistic.
+type JackknifeResult struct {
+	Estimate float64
+	Bias     float64
+	Variance float64
+	StdError float64
+	Values   []float64
+}
+
+func Jackknife(data []float64, stat func([]float64) float64) JackknifeResult {
+	samples := [][]float64{data}
+	values := jackknifeValues(samples, func(s [][]float64) float64 { return stat(s[0]) })
+	n := float64(len(values))
+	m := Mean(values)
+	ss := 0.0
+	for _, v := range values {
+		ss += (v - m) * (v - m)
+	}
+	res := JackknifeResult{
+		Estimate: stat(data),
+		Variance: (n - 1) / n * ss,
+		Values:   values,
+	}
+	res.Bias = (n - 1) * (m - res.Estimate)
+	res.StdError = math.Sqrt(res.Variance)
+	return res
+}
+
+// PermutationTest tests whether x and y come from the same distribution by
+// reassigning the pooled observations to groups of the original sizes. stat
+// defaults to the difference in means when nil. When there are at most
+// settings.Replicates distinct splits they are enumerated for an exact
+// p-value; otherwise random splits give (count+1)/(Replicates+1), which keeps
+// the test valid (Phipson & Smyth, 2010). Two-sided tests compare |stat|.
+func PermutationTest(x, y []float64, stat func(x, y []float64) float64, alt Alternative, settings BootstrapSettings) TestResult {
+	if stat == nil {
+		stat = func(x, y []float64) float64 { return Mean(x) - Mean(y) }
+	}
+	observed := stat(x, y)
+	pooled := append(append([]float64{}, x...), y...)
+	extreme := func(v float64) bool {
+		const eps = 1e-12
+		switch alt {
+		case Less:
+			return v <= observed+eps*math.Abs(observed)
+		case Greater:
+			return v >= observed-eps*math.Abs(observed)
+		}
+		return math.Abs(v) >= math.Abs(observed)*(1-eps)
+	}
+	res := TestResult{Statistic: observed, Estimate: observed,
+		CILower: math.NaN(), CIUpper: math.NaN(), EffectSize: math.NaN()}
+
+	n, m := len(x), len(y)
+	if total := math.Exp(logChoose(float64(n+m), float64(n))); total <= float64(settings.Replicates) {
+		count, all := 0, 0
+		chosen := make([]int, n)
+		for i := range chosen {
+			chosen[i] = i
+		}
+		gx, gy := make([]float64, n), make([]float64, 0, m)
+		for {
+			gy = gy[:0]
+			next := 0
+			for i, v := range pooled {
+				if next < n && chosen[next] == i {
+					gx[next] = v
+					next++
+				} else {
+					gy = append(gy, v)
+				}
+			}
+			if extreme(stat(gx, gy)) {
+				count++
+			}
+			all++
+			if !nextCombination(chosen, n+m) {
+				break
+			}
+		}
+		res.PValue = float64(count) / float64(all)
+		return res
+	}
+
+	var count atomic.Int64
+	parallelFor(settings.Replicates, settings.Workers, func(b int) {
+		rng := seededStream(settings.Seed, b)
+		perm := append([]float64{}, pooled...)
+		for i := len(perm) - 1; i > 0; i-- {
+			j := rng.IntN(i + 1)
+			perm[i], perm[j] = perm[j], perm[i]
+		}
+		if extreme(stat(perm[:n], perm[n:])) {
+			count.Add(1)
+		}
+	})
+	res.PValue = float64(count.Load()+1) / float64(settings.Replicates+1)
+	return res
+}
+
+// nextCombination advances c, an increasing k-subset of [0, n), to its
+// lexicographic successor, reporting false after the last one.
+func nextCombination(c []int, n int) bool {
+	k := len(c)
+	i := k - 1
+	for i >= 0 && c[i] == n-k+i {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	c[i]++
+	for j := i + 1; j < k; j++ {
+		c[j] = c[j-1] + 1
+	}
+	return true
+}
+
+// seededStream returns a sampler on the i-th 2^64-step stream of the PCG64
+// seeded with seed, so replicate or chain i is reproducible on any goroutine.
+func seededStream(seed uint64, i int) *random.Rand {
+	src := random.NewPCG64(seed, random.SplitMix64(seed))
+	src.Advance(uint64(i), 0)
+	return random.New(src)
+}
+
+func resample(rng *random.Rand, samples [][]float64) [][]float64 {
+	out := make([][]float64, len(samples))
+	for s, data := range samples {
+		out[s] = make([]float64, len(data))
+		for i := range out[s] {
+			out[s][i] = data[rng.IntN(len(data))]
+		}
+	}
+	return out
+}
+
+// jackknifeValues returns the statistic with each observation of each sample
+// left out in turn.
+func jackknifeValues(samples [][]float64, stat func([][]float64) float64) []float64 {
+	var values []float64
+	for s, data := range samples {
+		reduced := append([][]float64{}, samples...)
+		for i := range data {
+			reduced[s] = append(append([]float64{}, data[:i]...), data[i+1:]...)
+			values = append(values, stat(reduced))
+		}
+	}
+	return values
+}
+
+func sampleStdDev(data []float64) float64 {
+	n := float64(len(data))
+	return math.Sqrt(Variance(data) * n / (n - 1))
+}
+
+// sortedQuantile is the type 7 quantile of sorted data, as Percentile, with
+// NaN for p outside [0, 1].
+func sortedQuantile(sorted []float64, p float64) float64 {
+	if !(p >= 0 && p <= 1) || len(sorted) == 0 {
+		return math.NaN()
+	}
+	index := p * float64(len(sorted)-1)
+	lower := int(index)
+	if lower+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := index - float64(lower)
+	return sorted[lower]*(1-frac) + sorted[lower+1]*frac
+}
+
+// parallelFor runs body for 0 ≤ i < n on up to workers goroutines.
+func parallelFor(n, workers int, body func(i int)) {
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			body(i)
+		}
+		return
+	}
+	workers = min(workers, n)
+	var next atomic.Int64
+	next.Store(-1)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1))
+				if i >= n {
+					return
+				}
+				body(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
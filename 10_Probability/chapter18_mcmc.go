@@ -0,0 +1,712 @@
+// 2026 Update: Markov Chain Monte Carlo
+package probability
+
+import (
+	"math"
+	"runtime"
+
+	complexnums "github.com/mouaadid/MathsWithGolang/09_ComplexNumbers"
+	random "github.com/mouaadid/MathsWithGolang/16_Random"
+)
+
+// LogDensity is an unnormalised log posterior; it may return -Inf outside the
+// support.
+type LogDensity func(x []float64) float64
+
+// MCMCSettings controls the samplers. Each chain starts at x0 plus a uniform
+// jitter of ±InitRadius per coordinate and draws from its own PCG64 stream,
+// so results depend only on Seed, never on Workers. During Warmup each
+// sampler tunes itself and the draws are discarded.
+type MCMCSettings struct {
+	Chains     int
+	Warmup     int
+	Samples    int
+	Seed       uint64
+	Workers    int
+	InitRadius float64
+	// StepSize is the initial proposal scale (Metropolis–Hastings), slice
+	// width (slice sampler) or leapfrog step (HMC, NUTS); zero picks one.
+	StepSize float64
+	// TargetAccept is the mean acceptance statistic HMC and NUTS adapt
+	// their step size towards.
+	TargetAccept  float64
+	LeapfrogSteps int
+	MaxTreeDepth  int
+}
+
+func DefaultMCMCSettings() MCMCSettings {
+	return MCMCSettings{
+		Chains:        4,
+		Warmup:        1000,
+		Samples:       1000,
+		Seed:          42,
+		Workers:       runtime.GOMAXPROCS(0),
+		InitRadius:    2,
+		TargetAccept:  0.8,
+		LeapfrogSteps: 16,
+		MaxTreeDepth:  10,
+	}
+}
+
+// MCMCResult holds the post-warmup draws indexed [chain][draw][parameter],
+// each chain's acceptance rate and final step size, and the split R-hat and
+// effective sample size of each parameter. For NUTS the acceptance rate is
+// the mean acceptance statistic of the trajectories; for the slice sampler it
+// is 1 and the step size is the mean slice width.
+type MCMCResult struct {
+	Chains         [][][]float64
+	AcceptanceRate []float64
+	StepSize       []float64
+	RHat           []float64
+	ESS            []float64
+}
+
+// Draws returns parameter p indexed [chain][draw].
+func (r MCMCResult) Draws(p int) [][]float64 {
+	out := make([][]float64, len(r.Chains))
+	for c, chain := range r.Chains {
+		out[c] = make([]float64, len(chain))
+		for i, x := range chain {
+			out[c][i] = x[p]
+		}
+	}
+	return out
+}
+
+// Mean returns the posterior mean of each parameter over all chains.
+func (r MCMCResult) Mean() []float64 {
+	mean := make([]float64, len(r.RHat))
+	count := 0.0
+	for _, chain := range r.Chains {
+		for _, x := range chain {
+			count++
+			for j, v := range x {
+				mean[j] += (v - mean[j]) / count
+			}
+		}
+	}
+	return mean
+}
+
+// Autocorrelation returns the autocorrelation of parameter p at lags 0 to
+// maxLag, averaged over chains.
+func (r MCMCResult) Autocorrelation(p, maxLag int) []float64 {
+	out := make([]float64, maxLag+1)
+	draws := r.Draws(p)
+	for _, chain := range draws {
+		for k, v := range Autocorrelation(chain, maxLag) {
+			out[k] += v / float64(len(draws))
+		}
+	}
+	return out
+}
+
+type chainRun struct {
+	draws  [][]float64
+	accept float64
+	step   float64
+}
+
+func runChains(logp LogDensity, x0 []float64, settings MCMCSettings, sample func(rng *random.Rand, x []float64) chainRun) MCMCResult {
+	runs := make([]chainRun, settings.Chains)
+	parallelFor(settings.Chains, settings.Workers, func(c int) {
+		rng := seededStream(settings.Seed, c)
+		x := append([]float64{}, x0...)
+		for try := 0; try < 100; try++ {
+			for i := range x {
+				x[i] = x0[i] + settings.InitRadius*(2*rng.Float64()-1)
+			}
+			if lp := logp(x); !math.IsInf(lp, -1) && !math.IsNaN(lp) {
+				break
+			}
+			copy(x, x0)
+		}
+		runs[c] = sample(rng, x)
+	})
+	res := MCMCResult{}
+	for _, run := range runs {
+		res.Chains = append(res.Chains, run.draws)
+		res.AcceptanceRate = append(res.AcceptanceRate, run.accept)
+		res.StepSize = append(res.StepSize, run.step)
+	}
+	for p := range x0 {
+		draws := res.Draws(p)
+		res.RHat = append(res.RHat, SplitRHat(draws))
+		res.ESS = append(res.ESS, EffectiveSampleSize(draws))
+	}
+	return res
+}
+
+// MetropolisHastings runs random-walk Metropolis with a Gaussian proposal.
+// During warmup the proposal covariance tracks the empirical covariance of
+// the chain (Haario et al., 2001) and its scale is tuned towards the 0.234
+// acceptance rate that is optimal in high dimensions; both are then frozen.
+func MetropolisHastings(logp LogDensity, x0 []float64, settings MCMCSettings) MCMCResult {
+	return runChains(logp, x0, settings, func(rng *random.Rand, x []float64) chainRun {
+		d := len(x)
+		logScale := math.Log(2.38 / math.Sqrt(float64(d)))
+		if settings.StepSize > 0 {
+			logScale = math.Log(settings.StepSize)
+		}
+		chol := make([][]float64, d)
+		for i := range chol {
+			chol[i] = make([]float64, d)
+			chol[i][i] = 1
+		}
+		mean := make([]float64, d)
+		cov := make([][]float64, d)
+		for i := range cov {
+			cov[i] = make([]float64, d)
+		}
+		lp := logp(x)
+		prop := make([]float64, d)
+		z := make([]float64, d)
+		run := chainRun{}
+		accepted := 0
+		for it := 0; it < settings.Warmup+settings.Samples; it++ {
+			for i := range z {
+				z[i] = rng.Normal()
+			}
+			scale := math.Exp(logScale)
+			for i := range prop {
+				prop[i] = x[i]
+				for j := 0; j <= i; j++ {
+					prop[i] += scale * chol[i][j] * z[j]
+				}
+			}
+			lpProp := logp(prop)
+			acc := math.Min(1, math.Exp(lpProp-lp))
+			if math.IsNaN(acc) {
+				acc = 0
+			}
+			if rng.Float64() < acc {
+				copy(x, prop)
+				lp = lpProp
+				if it >= settings.Warmup {
+					accepted++
+				}
+			}
+			if it >= settings.Warmup {
+				run.draws = append(run.draws, append([]float64{}, x...))
+				continue
+			}
+			// Welford update of the running mean and covariance, a
+			// Robbins–Monro step on the log scale, and a periodic refresh of
+			// the proposal factor once enough draws have accumulated.
+			n := float64(it + 1)
+			delta := make([]float64, d)
+			for i := range x {
+				delta[i] = x[i] - mean[i]
+				mean[i] += delta[i] / n
+			}
+			for i := range cov {
+				for j := range cov[i] {
+					cov[i][j] += (delta[i]*(x[j]-mean[j]) - cov[i][j]) / n
+				}
+			}
+			logScale += (acc - 0.234) / math.Pow(n, 0.6)
+			if it >= 100 && (it+1)%50 == 0 {
+				reg := make([][]float64, d)
+				for i := range reg {
+					reg[i] = append([]float64{}, cov[i]...)
+					reg[i][i] += 1e-6*cov[i][i] + 1e-12
+				}
+				if l, ok := cholesky(reg); ok {
+					chol = l
+				}
+			}
+		}
+		run.accept = float64(accepted) / float64(max(settings.Samples, 1))
+		run.step = math.Exp(logScale)
+		return run
+	})
+}
+
+// SliceSampler updates one coordinate at a time with Neal's (2003) univariate
+// slice sampler, stepping out and shrinking the interval. Every update is
+// accepted; during warmup each coordinate's initial width is set to twice its
+// mean absolute move.
+func SliceSampler(logp LogDensity, x0 []float64, settings MCMCSettings) MCMCResult {
+	return runChains(logp, x0, settings, func(rng *random.Rand, x []float64) chainRun {
+		d := len(x)
+		width := make([]float64, d)
+		moved := make([]float64, d)
+		for i := range width {
+			width[i] = settings.StepSize
+			if width[i] <= 0 {
+				width[i] = 1
+			}
+		}
+		lp := logp(x)
+		work := append([]float64{}, x...)
+		at := func(i int, v float64) float64 {
+			work[i] = v
+			return logp(work)
+		}
+		run := chainRun{accept: 1}
+		for it := 0; it < settings.Warmup+settings.Samples; it++ {
+			for i := range x {
+				copy(work, x)
+				logy := lp - rng.Exponential()
+				left := x[i] - width[i]*rng.Float64()
+				right := left + width[i]
+				for k := 0; k < 100 && at(i, left) > logy; k++ {
+					left -= width[i]
+				}
+				for k := 0; k < 100 && at(i, right) > logy; k++ {
+					right += width[i]
+				}
+				for {
+					v := left + (right-left)*rng.Float64()
+					if lv := at(i, v); lv > logy {
+						moved[i] += math.Abs(v - x[i])
+						x[i], lp = v, lv
+						break
+					}
+					if v < x[i] {
+						left = v
+					} else {
+						right = v
+					}
+					if right-left < 1e-12*(1+math.Abs(x[i])) {
+						break
+					}
+				}
+			}
+			if it < settings.Warmup {
+				for i := range width {
+					if moved[i] > 0 {
+						width[i] = 2 * moved[i] / float64(it+1)
+					}
+				}
+				continue
+			}
+			run.draws = append(run.draws, append([]float64{}, x...))
+		}
+		run.step = Mean(width)
+		return run
+	})
+}
+
+// HMC runs Hamiltonian Monte Carlo with LeapfrogSteps steps per iteration.
+// NUTS chooses the trajectory length itself (Hoffman & Gelman, 2014). Both
+// tune the step size by dual averaging and, over the middle of warmup, a
+// diagonal mass matrix from the variance of the draws.
+func HMC(logp LogDensity, grad func([]float64) []float64, x0 []float64, settings MCMCSettings) MCMCResult {
+	return runChains(logp, x0, settings, func(rng *random.Rand, x []float64) chainRun {
+		h := newHamiltonian(logp, grad, x)
+		return h.run(rng, settings, func(rng *random.Rand, eps float64) float64 {
+			return h.staticTransition(rng, eps, settings.LeapfrogSteps)
+		})
+	})
+}
+
+func NUTS(logp LogDensity, grad func([]float64) []float64, x0 []float64, settings MCMCSettings) MCMCResult {
+	return runChains(logp, x0, settings, func(rng *random.Rand, x []float64) chainRun {
+		h := newHamiltonian(logp, grad, x)
+		return h.run(rng, settings, func(rng *random.Rand, eps float64) float64 {
+			return h.nutsTransition(rng, eps, settings.MaxTreeDepth)
+		})
+	})
+}
+
+// phaseState is a point in phase space with its log density and gradient.
+type phaseState struct {
+	x, r, g []float64
+	lp      float64
+}
+
+type hamiltonian struct {
+	logp    LogDensity
+	grad    func([]float64) []float64
+	invMass []float64
+	cur     phaseState
+}
+
+func newHamiltonian(logp LogDensity, grad func([]float64) []float64, x []float64) *hamiltonian {
+	h := &hamiltonian{logp: logp, grad: grad, invMass: make([]float64, len(x))}
+	for i := range h.invMass {
+		h.invMass[i] = 1
+	}
+	h.cur = phaseState{x: append([]float64{}, x...), g: grad(x), lp: logp(x)}
+	return h
+}
+
+// run adapts the step size by dual averaging on every warmup iteration, as
+// Stan does. Draws between 15% and 90% of warmup also feed a mass-matrix
+// window; once it closes, the metric is updated and the step size search
+// restarts, leaving the last 10% to settle the step for the new metric.
+func (h *hamiltonian) run(rng *random.Rand, settings MCMCSettings, transition func(*random.Rand, float64) float64) chainRun {
+	eps := settings.StepSize
+	if eps <= 0 {
+		eps = h.reasonableStepSize(rng)
+	}
+	da := newDualAveraging(eps, settings.TargetAccept)
+	windowStart, windowEnd := settings.Warmup*15/100, settings.Warmup*90/100
+	window := make([]RunningStats, len(h.cur.x))
+	run := chainRun{}
+	total := 0.0
+	for it := 0; it < settings.Warmup+settings.Samples; it++ {
+		if it < settings.Warmup {
+			accept := transition(rng, da.current())
+			da.update(accept)
+			if it >= windowStart && it < windowEnd {
+				for i, v := range h.cur.x {
+					window[i].Add(v)
+				}
+			}
+			if it == windowEnd-1 && windowEnd-windowStart > 10 {
+				// Shrink towards a small constant as Stan does.
+				n := float64(windowEnd - windowStart)
+				for i := range window {
+					h.invMass[i] = n/(n+5)*window[i].SampleVariance() + 1e-3*5/(n+5)
+				}
+				da = newDualAveraging(h.reasonableStepSize(rng), settings.TargetAccept)
+			}
+			continue
+		}
+		if it == settings.Warmup && settings.Warmup > 0 {
+			eps = da.final()
+		}
+		total += transition(rng, eps)
+		run.draws = append(run.draws, append([]float64{}, h.cur.x...))
+	}
+	run.accept = total / float64(max(settings.Samples, 1))
+	run.step = eps
+	return run
+}
+
+func (h *hamiltonian) kinetic(r []float64) float64 {
+	k := 0.0
+	for i, v := range r {
+		k += v * v * h.invMass[i]
+	}
+	return k / 2
+}
+
+func (h *hamiltonian) joint(s phaseState) float64 {
+	j := s.lp - h.kinetic(s.r)
+	if math.IsNaN(j) {
+		return math.Inf(-1)
+	}
+	return j
+}
+
+func (h *hamiltonian) momentum(rng *random.Rand) []float64 {
+	r := make([]float64, len(h.invMass))
+	for i := range r {
+		r[i] = rng.Normal() / math.Sqrt(h.invMass[i])
+	}
+	return r
+}
+
+func (h *hamiltonian) leapfrog(s phaseState, eps float64) phaseState {
+	next := phaseState{x: make([]float64, len(s.x)), r: make([]float64, len(s.r))}
+	for i := range s.r {
+		next.r[i] = s.r[i] + eps/2*s.g[i]
+		next.x[i] = s.x[i] + eps*h.invMass[i]*next.r[i]
+	}
+	next.g = h.grad(next.x)
+	next.lp = h.logp(next.x)
+	for i := range next.r {
+		next.r[i] += eps / 2 * next.g[i]
+	}
+	return next
+}
+
+// reasonableStepSize doubles or halves the step until a single leapfrog step
+// crosses an acceptance probability of one half (Hoffman & Gelman, Alg. 4).
+func (h *hamiltonian) reasonableStepSize(rng *random.Rand) float64 {
+	eps := 1.0
+	start := h.cur
+	start.r = h.momentum(rng)
+	logRatio := func() float64 {
+		return h.joint(h.leapfrog(start, eps)) - h.joint(start)
+	}
+	dir := -1.0
+	if logRatio() > math.Log(0.5) {
+		dir = 1
+	}
+	for k := 0; k < 100; k++ {
+		lr := logRatio()
+		if dir*lr <= dir*math.Log(0.5) {
+			break
+		}
+		eps *= math.Pow(2, dir)
+	}
+	return eps
+}
+
+func (h *hamiltonian) staticTransition(rng *random.Rand, eps float64, steps int) float64 {
+	start := h.cur
+	start.r = h.momentum(rng)
+	s := start
+	for k := 0; k < steps; k++ {
+		s = h.leapfrog(s, eps)
+	}
+	accept := math.Min(1, math.Exp(h.joint(s)-h.joint(start)))
+	if math.IsNaN(accept) {
+		accept = 0
+	}
+	if rng.Float64() < accept {
+		h.cur = s
+	}
+	return accept
+}
+
+type nutsTree struct {
+	minus, plus, proposal phaseState
+	n                     int
+	ok                    bool
+	alpha                 float64
+	nAlpha                int
+}
+
+// nutsTransition is the efficient NUTS of Hoffman & Gelman (Alg. 6) with a
+// slice variable, returning the mean acceptance statistic of the trajectory.
+func (h *hamiltonian) nutsTransition(rng *random.Rand, eps float64, maxDepth int) float64 {
+	start := h.cur
+	start.r = h.momentum(rng)
+	joint0 := h.joint(start)
+	logu := joint0 - rng.Exponential()
+	minus, plus := start, start
+	n := 1
+	alpha, nAlpha := 0.0, 0
+	for depth := 0; depth < maxDepth; depth++ {
+		var t nutsTree
+		if rng.Float64() < 0.5 {
+			t = h.buildTree(rng, minus, logu, -1, depth, eps, joint0)
+			minus = t.minus
+		} else {
+			t = h.buildTree(rng, plus, logu, 1, depth, eps, joint0)
+			plus = t.plus
+		}
+		alpha += t.alpha
+		nAlpha += t.nAlpha
+		if !t.ok {
+			break
+		}
+		if rng.Float64() < float64(t.n)/float64(n) {
+			h.cur = t.proposal
+		}
+		n += t.n
+		if !h.noUTurn(minus, plus) {
+			break
+		}
+	}
+	return alpha / float64(max(nAlpha, 1))
+}
+
+func (h *hamiltonian) buildTree(rng *random.Rand, s phaseState, logu float64, dir, depth int, eps, joint0 float64) nutsTree {
+	if depth == 0 {
+		next := h.leapfrog(s, float64(dir)*eps)
+		joint := h.joint(next)
+		t := nutsTree{minus: next, plus: next, proposal: next, ok: logu < joint+1000, nAlpha: 1}
+		if logu <= joint {
+			t.n = 1
+		}
+		t.alpha = math.Min(1, math.Exp(joint-joint0))
+		return t
+	}
+	t := h.buildTree(rng, s, logu, dir, depth-1, eps, joint0)
+	if !t.ok {
+		return t
+	}
+	var u nutsTree
+	if dir < 0 {
+		u = h.buildTree(rng, t.minus, logu, dir, depth-1, eps, joint0)
+		t.minus = u.minus
+	} else {
+		u = h.buildTree(rng, t.plus, logu, dir, depth-1, eps, joint0)
+		t.plus = u.plus
+	}
+	if u.n > 0 && rng.Float64() < float64(u.n)/float64(t.n+u.n) {
+		t.proposal = u.proposal
+	}
+	t.alpha += u.alpha
+	t.nAlpha += u.nAlpha
+	t.n += u.n
+	t.ok = u.ok && h.noUTurn(t.minus, t.plus)
+	return t
+}
+
+// noUTurn reports whether neither end of the trajectory is moving back
+// towards the other, measuring velocity through the inverse mass matrix.
+func (h *hamiltonian) noUTurn(minus, plus phaseState) bool {
+	a, b := 0.0, 0.0
+	for i := range minus.x {
+		dx := plus.x[i] - minus.x[i]
+		a += dx * h.invMass[i] * minus.r[i]
+		b += dx * h.invMass[i] * plus.r[i]
+	}
+	return a >= 0 && b >= 0
+}
+
+// dualAveraging is Nesterov's dual averaging as used for step sizes by
+// Hoffman & Gelman, with γ = 0.05, t₀ = 10 and κ = 0.75.
+type dualAveraging struct {
+	mu, hbar, logEps, logEpsBar, target, m float64
+}
+
+func newDualAveraging(eps, target float64) *dualAveraging {
+	return &dualAveraging{mu: math.Log(10 * eps), logEps: math.Log(eps), logEpsBar: math.Log(eps), target: target}
+}
+
+func (d *dualAveraging) update(accept float64) {
+	d.m++
+	w := 1 / (d.m + 10)
+	d.hbar = (1-w)*d.hbar + w*(d.target-accept)
+	d.logEps = d.mu - math.Sqrt(d.m)/0.05*d.hbar
+	eta := math.Pow(d.m, -0.75)
+	d.logEpsBar = eta*d.logEps + (1-eta)*d.logEpsBar
+}
+
+func (d *dualAveraging) current() float64 { return math.Exp(d.logEps) }
+func (d *dualAveraging) final() float64   { return math.Exp(d.logEpsBar) }
+
+func cholesky(a [][]float64) ([][]float64, bool) {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			s := a[i][j]
+			for k := 0; k < j; k++ {
+				s -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if s <= 0 {
+					return nil, false
+				}
+				l[i][i] = math.Sqrt(s)
+			} else {
+				l[i][j] = s / l[j][j]
+			}
+		}
+	}
+	return l, true
+}
+
+// Autocorrelation returns the sample autocorrelation of draws at lags 0 to
+// maxLag, computed by FFT.
+func Autocorrelation(draws []float64, maxLag int) []float64 {
+	acov := autocovariance(draws)
+	out := make([]float64, maxLag+1)
+	for k := range out {
+		if k < len(acov) && acov[0] > 0 {
+			out[k] = acov[k] / acov[0]
+		}
+	}
+	return out
+}
+
+// autocovariance returns the biased (divide by n) autocovariance at every lag.
+func autocovariance(draws []float64) []float64 {
+	n := len(draws)
+	if n == 0 {
+		return nil
+	}
+	size := 1
+	for size < 2*n {
+		size *= 2
+	}
+	m := Mean(draws)
+	buf := make([]complexnums.ComplexNumber, size)
+	for i, v := range draws {
+		buf[i].R = v - m
+	}
+	f := complexnums.FFT(buf)
+	for i, c := range f {
+		f[i] = complexnums.ComplexNumber{R: c.R*c.R + c.I*c.I}
+	}
+	inv := complexnums.IFFT(f)
+	out := make([]float64, n)
+	for k := range out {
+		out[k] = inv[k].R / float64(n)
+	}
+	return out
+}
+
+// splitChains halves every chain, dropping the middle draw of odd lengths,
+// so that trends within a chain show up as disagreement between halves.
+func splitChains(chains [][]float64) [][]float64 {
+	var out [][]float64
+	for _, c := range chains {
+		half := len(c) / 2
+		out = append(out, c[:half], c[len(c)-half:])
+	}
+	return out
+}
+
+// withinBetween returns the mean within-chain variance W and the pooled
+// estimate var⁺ = (n-1)/n·W + B/n of the posterior variance.
+func withinBetween(chains [][]float64) (float64, float64) {
+	n := float64(len(chains[0]))
+	means := make([]float64, len(chains))
+	w := 0.0
+	for c, chain := range chains {
+		means[c] = Mean(chain)
+		w += Variance(chain) * n / (n - 1)
+	}
+	w /= float64(len(chains))
+	between := 0.0
+	if len(chains) > 1 {
+		between = sampleStdDev(means)
+		between *= between
+	}
+	return w, (n-1)/n*w + between
+}
+
+// SplitRHat is the potential scale reduction factor of Gelman et al. (BDA3)
+// computed on split chains; values near 1 indicate that the chains agree.
+func SplitRHat(chains [][]float64) float64 {
+	split := splitChains(chains)
+	if len(split) == 0 || len(split[0]) < 2 {
+		return math.NaN()
+	}
+	w, varPlus := withinBetween(split)
+	if w == 0 {
+		return math.NaN()
+	}
+	return math.Sqrt(varPlus / w)
+}
+
+// EffectiveSampleSize estimates the number of independent draws the split
+// chains are worth, combining their autocorrelations with Geyer's initial
+// monotone sequence as in BDA3 and Stan.
+func EffectiveSampleSize(chains [][]float64) float64 {
+	split := splitChains(chains)
+	if len(split) == 0 || len(split[0]) < 4 {
+		return math.NaN()
+	}
+	m, n := len(split), len(split[0])
+	w, varPlus := withinBetween(split)
+	if w == 0 {
+		return math.NaN()
+	}
+	meanAcov := make([]float64, n)
+	for _, chain := range split {
+		for k, v := range autocovariance(chain) {
+			meanAcov[k] += v / float64(m)
+		}
+	}
+	rho := func(k int) float64 {
+		if k == 0 {
+			return 1
+		}
+		return 1 - (w-meanAcov[k])/varPlus
+	}
+	tau := -1.0
+	prev := math.Inf(1)
+	for k := 0; 2*k+1 < n; k++ {
+		pair := rho(2*k) + rho(2*k+1)
+		if pair <= 0 {
+			break
+		}
+		pair = math.Min(pair, prev)
+		tau += 2 * pair
+		prev = pair
+	}
+	total := float64(m * n)
+	return total / math.Max(tau, 1/math.Log10(total))
+}
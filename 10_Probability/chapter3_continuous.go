@@ -1,5 +1,7 @@
 package probability
 
+import "math"
+
 const Pi = 3.14159265358979323846
 
 func NormalPDF(x, mu, sigma float64) float64 {
@@ -19,6 +21,60 @@ func StandardNormalCDF(x float64) float64 {
 	return NormalCDF(x, 0, 1)
 }
 
+func NormalQuantile(p, mu, sigma float64) float64 {
+	return mu + sigma*StandardNormalQuantile(p)
+}
+
+// StandardNormalQuantile is Wichura's algorithm AS241 (PPND16), accurate to
+// about 1e-16 relative.
+func StandardNormalQuantile(p float64) float64 {
+	switch {
+	case math.IsNaN(p) || p < 0 || p > 1:
+		return math.NaN()
+	case p == 0:
+		return math.Inf(-1)
+	case p == 1:
+		return math.Inf(1)
+	}
+	q := p - 0.5
+	if math.Abs(q) <= 0.425 {
+		r := 0.180625 - q*q
+		return q * (((((((2.5090809287301226727e3*r+3.3430575583588128105e4)*r+6.7265770927008700853e4)*r+
+			4.5921953931549871457e4)*r+1.3731693765509461125e4)*r+1.9715909503065514427e3)*r+
+			1.3314166789178437745e2)*r + 3.3871328727963666080) /
+			(((((((5.2264952788528545610e3*r+2.8729085735721942674e4)*r+3.9307895800092710610e4)*r+
+				2.1213794301586595867e4)*r+5.3941960214247511077e3)*r+6.8718700749205790830e2)*r+
+				4.2313330701600911252e1)*r + 1)
+	}
+	r := p
+	if q > 0 {
+		r = 1 - p
+	}
+	r = math.Sqrt(-math.Log(r))
+	var z float64
+	if r <= 5 {
+		r -= 1.6
+		z = (((((((7.74545014278341407640e-4*r+2.27238449892691845833e-2)*r+2.41780725177450611770e-1)*r+
+			1.27045825245236838258)*r+3.64784832476320460504)*r+5.76949722146069140550)*r+
+			4.63033784615654529590)*r + 1.42343711074968357734) /
+			(((((((1.05075007164441684324e-9*r+5.47593808499534494600e-4)*r+1.51986665636164571966e-2)*r+
+				1.48103976427480074590e-1)*r+6.89767334985100004550e-1)*r+1.67638483018380384940)*r+
+				2.05319162663775882187)*r + 1)
+	} else {
+		r -= 5
+		z = (((((((2.01033439929228813265e-7*r+2.71155556874348757815e-5)*r+1.24266094738807843860e-3)*r+
+			2.65321895265761230930e-2)*r+2.96560571828504891230e-1)*r+1.78482653991729133580)*r+
+			5.46378491116411436990)*r + 6.65790464350110377720) /
+			(((((((2.04426310338993978564e-15*r+1.42151175831644588870e-7)*r+1.84631831751005468180e-5)*r+
+				7.86869131145613259100e-4)*r+1.48753612908506148525e-2)*r+1.36929880922735805310e-1)*r+
+				5.99832206555887937690e-1)*r + 1)
+	}
+	if q < 0 {
+		return -z
+	}
+	return z
+}
+
 func ExponentialPDF(x, lambda float64) float64 {
 	if x < 0 {
 		return 0
@@ -57,6 +113,14 @@ func GammaPDF(x, alpha, beta float64) float64 {
 	return powerP(beta, alpha) * powerP(x, alpha-1) * expP(-beta*x) / gammaFunc(alpha)
 }
 
+func GammaCDF(x, alpha, beta float64) float64 {
+	return Gamma{Shape: alpha, Rate: beta}.CDF(x)
+}
+
+func GammaQuantile(p, alpha, beta float64) float64 {
+	return Gamma{Shape: alpha, Rate: beta}.Quantile(p)
+}
+
 func BetaPDF(x, alpha, beta float64) float64 {
 	if x <= 0 || x >= 1 {
 		return 0
@@ -64,6 +128,14 @@ func BetaPDF(x, alpha, beta float64) float64 {
 	return powerP(x, alpha-1) * powerP(1-x, beta-1) / betaFunc(alpha, beta)
 }
 
+func BetaCDF(x, alpha, beta float64) float64 {
+	return Beta{Alpha: alpha, Beta: beta}.CDF(x)
+}
+
+func BetaQuantile(p, alpha, beta float64) float64 {
+	return Beta{Alpha: alpha, Beta: beta}.Quantile(p)
+}
+
 func gammaFunc(z float64) float64 {
 	g := 7
 	c := []float64{
@@ -75,7 +75,8 @@ func Skewness(data []float64) float64 {
 	}
 	sum := 0.0
 	for _, v := range data {
-		sum += powerP((v-m)/s, 3)
+		z := (v - m) / s
+		sum += z * z * z
 	}
 	return sum / n
 }
@@ -92,7 +93,8 @@ func Kurtosis(data []float64) float64 {
 	}
 	sum := 0.0
 	for _, v := range data {
-		sum += powerP((v-m)/s, 4)
+		z := (v - m) / s
+		sum += z * z * z * z
 	}
 	return sum/n - 3
 }
@@ -14,6 +14,30 @@ func TDistPDF(t float64, df int) float64 {
 	return coef * powerP(1+t*t/v, -(v+1)/2)
 }
 
+func ChiSquareCDF(x float64, k int) float64 {
+	return ChiSquared{K: float64(k)}.CDF(x)
+}
+
+func ChiSquareQuantile(p float64, k int) float64 {
+	return ChiSquared{K: float64(k)}.Quantile(p)
+}
+
+func TDistCDF(t float64, df int) float64 {
+	return StudentT{Nu: float64(df)}.CDF(t)
+}
+
+func TDistQuantile(p float64, df int) float64 {
+	return StudentT{Nu: float64(df)}.Quantile(p)
+}
+
+func FDistCDF(x float64, d1, d2 int) float64 {
+	return FisherF{D1: float64(d1), D2: float64(d2)}.CDF(x)
+}
+
+func FDistQuantile(p float64, d1, d2 int) float64 {
+	return FisherF{D1: float64(d1), D2: float64(d2)}.Quantile(p)
+}
+
 func ZTest(sampleMean, popMean, popStd float64, n int) float64 {
 	return (sampleMean - popMean) / (popStd / sqrtP(float64(n)))
 }
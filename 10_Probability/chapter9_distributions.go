@@ -0,0 +1,952 @@
+// 2026 Update: Distribution Interface
+package probability
+
+import (
+	"math"
+
+	functions "github.com/mouaadid/MathsWithGolang/05_Functions"
+)
+
+const eulerGamma = 0.57721566490153286061
+
+// RandomSource is any generator of uniform variates on [0, 1); *LCG satisfies it.
+type RandomSource interface {
+	Float64() float64
+}
+
+type Distribution interface {
+	PDF(x float64) float64
+	LogPDF(x float64) float64
+	CDF(x float64) float64
+	Survival(x float64) float64
+	Quantile(p float64) float64
+	Mean() float64
+	Variance() float64
+	Entropy() float64
+	Sample(rng RandomSource) float64
+}
+
+// DiscreteDistribution is the integer-valued counterpart of Distribution.
+// Quantile returns the smallest k with CDF(k) >= p.
+type DiscreteDistribution interface {
+	PMF(k int) float64
+	LogPMF(k int) float64
+	CDF(k int) float64
+	Survival(k int) float64
+	Quantile(p float64) int
+	Mean() float64
+	Variance() float64
+	Entropy() float64
+	Sample(rng RandomSource) int
+}
+
+type Normal struct {
+	Mu    float64
+	Sigma float64
+}
+
+func (d Normal) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Normal) LogPDF(x float64) float64 {
+	z := (x - d.Mu) / d.Sigma
+	return -0.5*z*z - math.Log(d.Sigma*math.Sqrt(2*math.Pi))
+}
+
+func (d Normal) CDF(x float64) float64 {
+	return 0.5 * math.Erfc(-(x-d.Mu)/(d.Sigma*math.Sqrt2))
+}
+
+func (d Normal) Survival(x float64) float64 {
+	return 0.5 * math.Erfc((x-d.Mu)/(d.Sigma*math.Sqrt2))
+}
+
+func (d Normal) Quantile(p float64) float64 {
+	return d.Mu + d.Sigma*StandardNormalQuantile(p)
+}
+
+func (d Normal) Mean() float64     { return d.Mu }
+func (d Normal) Variance() float64 { return d.Sigma * d.Sigma }
+
+func (d Normal) Entropy() float64 {
+	return 0.5 * math.Log(2*math.Pi*math.E*d.Sigma*d.Sigma)
+}
+
+func (d Normal) Sample(rng RandomSource) float64 {
+	return d.Mu + d.Sigma*standardNormal(rng)
+}
+
+type LogNormal struct {
+	Mu    float64
+	Sigma float64
+}
+
+func (d LogNormal) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d LogNormal) LogPDF(x float64) float64 {
+	if x <= 0 {
+		return math.Inf(-1)
+	}
+	return Normal{d.Mu, d.Sigma}.LogPDF(math.Log(x)) - math.Log(x)
+}
+
+func (d LogNormal) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return Normal{d.Mu, d.Sigma}.CDF(math.Log(x))
+}
+
+func (d LogNormal) Survival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return Normal{d.Mu, d.Sigma}.Survival(math.Log(x))
+}
+
+func (d LogNormal) Quantile(p float64) float64 {
+	return math.Exp(Normal{d.Mu, d.Sigma}.Quantile(p))
+}
+
+func (d LogNormal) Mean() float64 { return math.Exp(d.Mu + d.Sigma*d.Sigma/2) }
+
+func (d LogNormal) Variance() float64 {
+	s2 := d.Sigma * d.Sigma
+	return math.Expm1(s2) * math.Exp(2*d.Mu+s2)
+}
+
+func (d LogNormal) Entropy() float64 { return d.Mu + Normal{d.Mu, d.Sigma}.Entropy() }
+
+func (d LogNormal) Sample(rng RandomSource) float64 {
+	return math.Exp(d.Mu + d.Sigma*standardNormal(rng))
+}
+
+type Exponential struct {
+	Rate float64
+}
+
+func (d Exponential) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Exponential) LogPDF(x float64) float64 {
+	if x < 0 {
+		return math.Inf(-1)
+	}
+	return math.Log(d.Rate) - d.Rate*x
+}
+
+func (d Exponential) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return -math.Expm1(-d.Rate * x)
+}
+
+func (d Exponential) Survival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return math.Exp(-d.Rate * x)
+}
+
+func (d Exponential) Quantile(p float64) float64 { return -math.Log1p(-p) / d.Rate }
+func (d Exponential) Mean() float64              { return 1 / d.Rate }
+func (d Exponential) Variance() float64          { return 1 / (d.Rate * d.Rate) }
+func (d Exponential) Entropy() float64           { return 1 - math.Log(d.Rate) }
+
+func (d Exponential) Sample(rng RandomSource) float64 {
+	return -math.Log(1-rng.Float64()) / d.Rate
+}
+
+// Gamma uses the shape/rate parameterization of GammaPDF.
+type Gamma struct {
+	Shape float64
+	Rate  float64
+}
+
+func (d Gamma) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Gamma) LogPDF(x float64) float64 {
+	if x < 0 {
+		return math.Inf(-1)
+	}
+	return d.Shape*math.Log(d.Rate) + xlogy(d.Shape-1, x) - d.Rate*x - lgamma(d.Shape)
+}
+
+func (d Gamma) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return functions.RegularizedGammaP(d.Shape, d.Rate*x)
+}
+
+func (d Gamma) Survival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return functions.RegularizedGammaQ(d.Shape, d.Rate*x)
+}
+
+func (d Gamma) Quantile(p float64) float64 {
+	return functions.InverseRegularizedGammaP(d.Shape, p) / d.Rate
+}
+
+func (d Gamma) Mean() float64     { return d.Shape / d.Rate }
+func (d Gamma) Variance() float64 { return d.Shape / (d.Rate * d.Rate) }
+
+func (d Gamma) Entropy() float64 {
+	return d.Shape - math.Log(d.Rate) + lgamma(d.Shape) + (1-d.Shape)*digamma(d.Shape)
+}
+
+func (d Gamma) Sample(rng RandomSource) float64 {
+	return gammaVariate(rng, d.Shape) / d.Rate
+}
+
+type Beta struct {
+	Alpha float64
+	Beta  float64
+}
+
+func (d Beta) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Beta) LogPDF(x float64) float64 {
+	if x < 0 || x > 1 {
+		return math.Inf(-1)
+	}
+	return xlogy(d.Alpha-1, x) + xlogy(d.Beta-1, 1-x) - lbeta(d.Alpha, d.Beta)
+}
+
+func (d Beta) CDF(x float64) float64 {
+	return functions.RegularizedBeta(d.Alpha, d.Beta, clampUnit(x))
+}
+
+func (d Beta) Survival(x float64) float64 {
+	return functions.RegularizedBeta(d.Beta, d.Alpha, clampUnit(1-x))
+}
+
+func (d Beta) Quantile(p float64) float64 {
+	return functions.InverseRegularizedBeta(d.Alpha, d.Beta, p)
+}
+
+func (d Beta) Mean() float64 { return d.Alpha / (d.Alpha + d.Beta) }
+
+func (d Beta) Variance() float64 {
+	s := d.Alpha + d.Beta
+	return d.Alpha * d.Beta / (s * s * (s + 1))
+}
+
+func (d Beta) Entropy() float64 {
+	a, b := d.Alpha, d.Beta
+	return lbeta(a, b) - (a-1)*digamma(a) - (b-1)*digamma(b) + (a+b-2)*digamma(a+b)
+}
+
+func (d Beta) Sample(rng RandomSource) float64 {
+	x := gammaVariate(rng, d.Alpha)
+	return x / (x + gammaVariate(rng, d.Beta))
+}
+
+// ChiSquared allows non-integer degrees of freedom K.
+type ChiSquared struct {
+	K float64
+}
+
+func (d ChiSquared) gamma() Gamma { return Gamma{Shape: d.K / 2, Rate: 0.5} }
+
+func (d ChiSquared) PDF(x float64) float64           { return d.gamma().PDF(x) }
+func (d ChiSquared) LogPDF(x float64) float64        { return d.gamma().LogPDF(x) }
+func (d ChiSquared) CDF(x float64) float64           { return d.gamma().CDF(x) }
+func (d ChiSquared) Survival(x float64) float64      { return d.gamma().Survival(x) }
+func (d ChiSquared) Quantile(p float64) float64      { return d.gamma().Quantile(p) }
+func (d ChiSquared) Mean() float64                   { return d.K }
+func (d ChiSquared) Variance() float64               { return 2 * d.K }
+func (d ChiSquared) Entropy() float64                { return d.gamma().Entropy() }
+func (d ChiSquared) Sample(rng RandomSource) float64 { return d.gamma().Sample(rng) }
+
+type StudentT struct {
+	Nu float64
+}
+
+func (d StudentT) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d StudentT) LogPDF(x float64) float64 {
+	v := d.Nu
+	return -lbeta(v/2, 0.5) - 0.5*math.Log(v) - (v+1)/2*math.Log1p(x*x/v)
+}
+
+func (d StudentT) CDF(x float64) float64 {
+	if x > 0 {
+		return 1 - d.tail(x)
+	}
+	return d.tail(x)
+}
+
+func (d StudentT) Survival(x float64) float64 { return d.CDF(-x) }
+
+// tail returns P(T > |x|), choosing the incomplete beta argument that avoids
+// cancellation near the centre.
+func (d StudentT) tail(x float64) float64 {
+	v, x2 := d.Nu, x*x
+	if x2 < v {
+		return 0.5 * (1 - functions.RegularizedBeta(0.5, v/2, x2/(v+x2)))
+	}
+	return 0.5 * functions.RegularizedBeta(v/2, 0.5, v/(v+x2))
+}
+
+// Quantile inverts the same incomplete beta forms as tail, switching at a
+// two-sided tail probability of one half.
+func (d StudentT) Quantile(p float64) float64 {
+	v := d.Nu
+	tail := 2 * math.Min(p, 1-p)
+	var t float64
+	if tail < 0.5 {
+		x := functions.InverseRegularizedBeta(v/2, 0.5, tail)
+		t = math.Sqrt(v * (1 - x) / x)
+	} else {
+		y := functions.InverseRegularizedBeta(0.5, v/2, 1-tail)
+		t = math.Sqrt(v * y / (1 - y))
+	}
+	if p < 0.5 {
+		return -t
+	}
+	return t
+}
+
+func (d StudentT) Mean() float64 {
+	if d.Nu <= 1 {
+		return math.NaN()
+	}
+	return 0
+}
+
+func (d StudentT) Variance() float64 {
+	switch {
+	case d.Nu > 2:
+		return d.Nu / (d.Nu - 2)
+	case d.Nu > 1:
+		return math.Inf(1)
+	}
+	return math.NaN()
+}
+
+func (d StudentT) Entropy() float64 {
+	v := d.Nu
+	return (v+1)/2*(digamma((v+1)/2)-digamma(v/2)) + 0.5*math.Log(v) + lbeta(v/2, 0.5)
+}
+
+func (d StudentT) Sample(rng RandomSource) float64 {
+	return standardNormal(rng) / math.Sqrt(2*gammaVariate(rng, d.Nu/2)/d.Nu)
+}
+
+type FisherF struct {
+	D1 float64
+	D2 float64
+}
+
+func (d FisherF) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d FisherF) LogPDF(x float64) float64 {
+	if x < 0 {
+		return math.Inf(-1)
+	}
+	a, b := d.D1/2, d.D2/2
+	return a*math.Log(d.D1/d.D2) + xlogy(a-1, x) - (a+b)*math.Log1p(d.D1*x/d.D2) - lbeta(a, b)
+}
+
+func (d FisherF) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return functions.RegularizedBeta(d.D1/2, d.D2/2, d.D1*x/(d.D1*x+d.D2))
+}
+
+func (d FisherF) Survival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return functions.RegularizedBeta(d.D2/2, d.D1/2, d.D2/(d.D1*x+d.D2))
+}
+
+func (d FisherF) Quantile(p float64) float64 {
+	if p > 0.5 {
+		y := functions.InverseRegularizedBeta(d.D2/2, d.D1/2, 1-p)
+		return d.D2 * (1 - y) / (d.D1 * y)
+	}
+	x := functions.InverseRegularizedBeta(d.D1/2, d.D2/2, p)
+	return d.D2 * x / (d.D1 * (1 - x))
+}
+
+func (d FisherF) Mean() float64 {
+	if d.D2 <= 2 {
+		return math.Inf(1)
+	}
+	return d.D2 / (d.D2 - 2)
+}
+
+func (d FisherF) Variance() float64 {
+	d1, d2 := d.D1, d.D2
+	switch {
+	case d2 > 4:
+		return 2 * d2 * d2 * (d1 + d2 - 2) / (d1 * (d2 - 2) * (d2 - 2) * (d2 - 4))
+	case d2 > 2:
+		return math.Inf(1)
+	}
+	return math.NaN()
+}
+
+func (d FisherF) Entropy() float64 {
+	a, b := d.D1/2, d.D2/2
+	return math.Log(d.D2/d.D1) + lbeta(a, b) + (1-a)*digamma(a) - (1+b)*digamma(b) + (a+b)*digamma(a+b)
+}
+
+func (d FisherF) Sample(rng RandomSource) float64 {
+	x := gammaVariate(rng, d.D1/2) / d.D1
+	return x / (gammaVariate(rng, d.D2/2) / d.D2)
+}
+
+type Weibull struct {
+	Shape float64
+	Scale float64
+}
+
+func (d Weibull) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Weibull) LogPDF(x float64) float64 {
+	if x < 0 {
+		return math.Inf(-1)
+	}
+	z := x / d.Scale
+	return math.Log(d.Shape/d.Scale) + xlogy(d.Shape-1, z) - math.Pow(z, d.Shape)
+}
+
+func (d Weibull) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return -math.Expm1(-math.Pow(x/d.Scale, d.Shape))
+}
+
+func (d Weibull) Survival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return math.Exp(-math.Pow(x/d.Scale, d.Shape))
+}
+
+func (d Weibull) Quantile(p float64) float64 {
+	return d.Scale * math.Pow(-math.Log1p(-p), 1/d.Shape)
+}
+
+func (d Weibull) Mean() float64 { return d.Scale * math.Gamma(1+1/d.Shape) }
+
+func (d Weibull) Variance() float64 {
+	g1 := math.Gamma(1 + 1/d.Shape)
+	return d.Scale * d.Scale * (math.Gamma(1+2/d.Shape) - g1*g1)
+}
+
+func (d Weibull) Entropy() float64 {
+	return eulerGamma*(1-1/d.Shape) + math.Log(d.Scale/d.Shape) + 1
+}
+
+func (d Weibull) Sample(rng RandomSource) float64 { return d.Quantile(rng.Float64()) }
+
+type Cauchy struct {
+	Location float64
+	Scale    float64
+}
+
+func (d Cauchy) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Cauchy) LogPDF(x float64) float64 {
+	z := (x - d.Location) / d.Scale
+	return -math.Log(math.Pi*d.Scale) - math.Log1p(z*z)
+}
+
+func (d Cauchy) CDF(x float64) float64 {
+	return math.Atan2(1, -(x-d.Location)/d.Scale) / math.Pi
+}
+
+func (d Cauchy) Survival(x float64) float64 {
+	return math.Atan2(1, (x-d.Location)/d.Scale) / math.Pi
+}
+
+func (d Cauchy) Quantile(p float64) float64 {
+	switch {
+	case p <= 0:
+		return math.Inf(-1)
+	case p >= 1:
+		return math.Inf(1)
+	}
+	return d.Location + d.Scale*math.Tan(math.Pi*(p-0.5))
+}
+
+func (d Cauchy) Mean() float64     { return math.NaN() }
+func (d Cauchy) Variance() float64 { return math.NaN() }
+func (d Cauchy) Entropy() float64  { return math.Log(4 * math.Pi * d.Scale) }
+
+func (d Cauchy) Sample(rng RandomSource) float64 { return d.Quantile(rng.Float64()) }
+
+type Laplace struct {
+	Mu float64
+	B  float64
+}
+
+func (d Laplace) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Laplace) LogPDF(x float64) float64 {
+	return -math.Log(2*d.B) - math.Abs(x-d.Mu)/d.B
+}
+
+func (d Laplace) CDF(x float64) float64 {
+	if x < d.Mu {
+		return 0.5 * math.Exp((x-d.Mu)/d.B)
+	}
+	return 1 - 0.5*math.Exp(-(x-d.Mu)/d.B)
+}
+
+func (d Laplace) Survival(x float64) float64 { return d.CDF(2*d.Mu - x) }
+
+func (d Laplace) Quantile(p float64) float64 {
+	if p < 0.5 {
+		return d.Mu + d.B*math.Log(2*p)
+	}
+	return d.Mu - d.B*math.Log(2*(1-p))
+}
+
+func (d Laplace) Mean() float64     { return d.Mu }
+func (d Laplace) Variance() float64 { return 2 * d.B * d.B }
+func (d Laplace) Entropy() float64  { return 1 + math.Log(2*d.B) }
+
+func (d Laplace) Sample(rng RandomSource) float64 { return d.Quantile(rng.Float64()) }
+
+type Logistic struct {
+	Mu float64
+	S  float64
+}
+
+func (d Logistic) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Logistic) LogPDF(x float64) float64 {
+	z := math.Abs(x-d.Mu) / d.S
+	return -z - math.Log(d.S) - 2*math.Log1p(math.Exp(-z))
+}
+
+func (d Logistic) CDF(x float64) float64      { return 1 / (1 + math.Exp(-(x-d.Mu)/d.S)) }
+func (d Logistic) Survival(x float64) float64 { return 1 / (1 + math.Exp((x-d.Mu)/d.S)) }
+
+func (d Logistic) Quantile(p float64) float64 {
+	return d.Mu + d.S*math.Log(p/(1-p))
+}
+
+func (d Logistic) Mean() float64     { return d.Mu }
+func (d Logistic) Variance() float64 { return d.S * d.S * math.Pi * math.Pi / 3 }
+func (d Logistic) Entropy() float64  { return math.Log(d.S) + 2 }
+
+func (d Logistic) Sample(rng RandomSource) float64 { return d.Quantile(rng.Float64()) }
+
+type Pareto struct {
+	Xm    float64
+	Alpha float64
+}
+
+func (d Pareto) PDF(x float64) float64 { return math.Exp(d.LogPDF(x)) }
+
+func (d Pareto) LogPDF(x float64) float64 {
+	if x < d.Xm {
+		return math.Inf(-1)
+	}
+	return math.Log(d.Alpha) + d.Alpha*math.Log(d.Xm) - (d.Alpha+1)*math.Log(x)
+}
+
+func (d Pareto) CDF(x float64) float64 {
+	if x <= d.Xm {
+		return 0
+	}
+	return -math.Expm1(d.Alpha * math.Log(d.Xm/x))
+}
+
+func (d Pareto) Survival(x float64) float64 {
+	if x <= d.Xm {
+		return 1
+	}
+	return math.Pow(d.Xm/x, d.Alpha)
+}
+
+func (d Pareto) Quantile(p float64) float64 {
+	return d.Xm * math.Pow(1-p, -1/d.Alpha)
+}
+
+func (d Pareto) Mean() float64 {
+	if d.Alpha <= 1 {
+		return math.Inf(1)
+	}
+	return d.Alpha * d.Xm / (d.Alpha - 1)
+}
+
+func (d Pareto) Variance() float64 {
+	if d.Alpha <= 2 {
+		return math.Inf(1)
+	}
+	a := d.Alpha
+	return d.Xm * d.Xm * a / ((a - 1) * (a - 1) * (a - 2))
+}
+
+func (d Pareto) Entropy() float64 { return math.Log(d.Xm/d.Alpha) + 1/d.Alpha + 1 }
+
+func (d Pareto) Sample(rng RandomSource) float64 {
+	return d.Xm * math.Pow(1-rng.Float64(), -1/d.Alpha)
+}
+
+type Uniform struct {
+	A float64
+	B float64
+}
+
+func (d Uniform) PDF(x float64) float64 { return UniformPDF(x, d.A, d.B) }
+
+func (d Uniform) LogPDF(x float64) float64 { return math.Log(d.PDF(x)) }
+
+func (d Uniform) CDF(x float64) float64      { return UniformCDF(x, d.A, d.B) }
+func (d Uniform) Survival(x float64) float64 { return UniformCDF(d.A+d.B-x, d.A, d.B) }
+
+func (d Uniform) Quantile(p float64) float64 {
+	return d.A + p*(d.B-d.A)
+}
+
+func (d Uniform) Mean() float64     { return (d.A + d.B) / 2 }
+func (d Uniform) Variance() float64 { return (d.B - d.A) * (d.B - d.A) / 12 }
+func (d Uniform) Entropy() float64  { return math.Log(d.B - d.A) }
+
+func (d Uniform) Sample(rng RandomSource) float64 { return d.Quantile(rng.Float64()) }
+
+type Binomial struct {
+	N int
+	P float64
+}
+
+func (d Binomial) PMF(k int) float64 { return math.Exp(d.LogPMF(k)) }
+
+func (d Binomial) LogPMF(k int) float64 {
+	if k < 0 || k > d.N {
+		return math.Inf(-1)
+	}
+	return lchoose(d.N, k) + xlogy(float64(k), d.P) + xlogy(float64(d.N-k), 1-d.P)
+}
+
+func (d Binomial) CDF(k int) float64 {
+	switch {
+	case k < 0:
+		return 0
+	case k >= d.N:
+		return 1
+	}
+	return functions.RegularizedBeta(float64(d.N-k), float64(k+1), 1-d.P)
+}
+
+func (d Binomial) Survival(k int) float64 {
+	switch {
+	case k < 0:
+		return 1
+	case k >= d.N:
+		return 0
+	}
+	return functions.RegularizedBeta(float64(k+1), float64(d.N-k), d.P)
+}
+
+func (d Binomial) Quantile(p float64) int { return discreteQuantile(d.CDF, p, 0, d.N) }
+func (d Binomial) Mean() float64          { return float64(d.N) * d.P }
+func (d Binomial) Variance() float64      { return float64(d.N) * d.P * (1 - d.P) }
+func (d Binomial) Entropy() float64       { return discreteEntropy(d) }
+
+func (d Binomial) Sample(rng RandomSource) int { return d.Quantile(rng.Float64()) }
+
+type Poisson struct {
+	Lambda float64
+}
+
+func (d Poisson) PMF(k int) float64 { return math.Exp(d.LogPMF(k)) }
+
+func (d Poisson) LogPMF(k int) float64 {
+	if k < 0 {
+		return math.Inf(-1)
+	}
+	return xlogy(float64(k), d.Lambda) - d.Lambda - lgamma(float64(k+1))
+}
+
+func (d Poisson) CDF(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+	return functions.RegularizedGammaQ(float64(k+1), d.Lambda)
+}
+
+func (d Poisson) Survival(k int) float64 {
+	if k < 0 {
+		return 1
+	}
+	return functions.RegularizedGammaP(float64(k+1), d.Lambda)
+}
+
+func (d Poisson) Quantile(p float64) int { return discreteQuantile(d.CDF, p, 0, -1) }
+func (d Poisson) Mean() float64          { return d.Lambda }
+func (d Poisson) Variance() float64      { return d.Lambda }
+func (d Poisson) Entropy() float64       { return discreteEntropy(d) }
+
+func (d Poisson) Sample(rng RandomSource) int { return d.Quantile(rng.Float64()) }
+
+// Geometric counts the trials up to and including the first success, as in
+// GeometricPMF, so its support starts at 1.
+type Geometric struct {
+	P float64
+}
+
+func (d Geometric) PMF(k int) float64 { return math.Exp(d.LogPMF(k)) }
+
+func (d Geometric) LogPMF(k int) float64 {
+	if k < 1 {
+		return math.Inf(-1)
+	}
+	return math.Log(d.P) + xlogy(float64(k-1), 1-d.P)
+}
+
+func (d Geometric) CDF(k int) float64 {
+	if k < 1 {
+		return 0
+	}
+	return -math.Expm1(float64(k) * math.Log1p(-d.P))
+}
+
+func (d Geometric) Survival(k int) float64 { return 1 - d.CDF(k) }
+
+func (d Geometric) Quantile(p float64) int { return discreteQuantile(d.CDF, p, 1, -1) }
+func (d Geometric) Mean() float64          { return 1 / d.P }
+func (d Geometric) Variance() float64      { return (1 - d.P) / (d.P * d.P) }
+
+func (d Geometric) Entropy() float64 {
+	return -(xlogy(1-d.P, 1-d.P) + xlogy(d.P, d.P)) / d.P
+}
+
+func (d Geometric) Sample(rng RandomSource) int { return d.Quantile(rng.Float64()) }
+
+// NegativeBinomial counts the trials needed for R successes, as in
+// NegativeBinomialPMF, so its support starts at R.
+type NegativeBinomial struct {
+	R int
+	P float64
+}
+
+func (d NegativeBinomial) PMF(k int) float64 { return math.Exp(d.LogPMF(k)) }
+
+func (d NegativeBinomial) LogPMF(k int) float64 {
+	if k < d.R {
+		return math.Inf(-1)
+	}
+	return lchoose(k-1, d.R-1) + xlogy(float64(d.R), d.P) + xlogy(float64(k-d.R), 1-d.P)
+}
+
+func (d NegativeBinomial) CDF(k int) float64 {
+	if k < d.R {
+		return 0
+	}
+	return functions.RegularizedBeta(float64(d.R), float64(k-d.R+1), d.P)
+}
+
+func (d NegativeBinomial) Survival(k int) float64 {
+	if k < d.R {
+		return 1
+	}
+	return functions.RegularizedBeta(float64(k-d.R+1), float64(d.R), 1-d.P)
+}
+
+func (d NegativeBinomial) Quantile(p float64) int {
+	return discreteQuantile(d.CDF, p, d.R, -1)
+}
+
+func (d NegativeBinomial) Mean() float64     { return float64(d.R) / d.P }
+func (d NegativeBinomial) Variance() float64 { return float64(d.R) * (1 - d.P) / (d.P * d.P) }
+func (d NegativeBinomial) Entropy() float64  { return discreteEntropy(d) }
+
+func (d NegativeBinomial) Sample(rng RandomSource) int { return d.Quantile(rng.Float64()) }
+
+// Hypergeometric counts successes in Draws draws without replacement from a
+// population of size Population containing Successes successes.
+type Hypergeometric struct {
+	Population int
+	Successes  int
+	Draws      int
+}
+
+func (d Hypergeometric) support() (int, int) {
+	lo := d.Draws + d.Successes - d.Population
+	if lo < 0 {
+		lo = 0
+	}
+	hi := d.Draws
+	if d.Successes < hi {
+		hi = d.Successes
+	}
+	return lo, hi
+}
+
+func (d Hypergeometric) PMF(k int) float64 { return math.Exp(d.LogPMF(k)) }
+
+func (d Hypergeometric) LogPMF(k int) float64 {
+	lo, hi := d.support()
+	if k < lo || k > hi {
+		return math.Inf(-1)
+	}
+	return lchoose(d.Successes, k) + lchoose(d.Population-d.Successes, d.Draws-k) - lchoose(d.Population, d.Draws)
+}
+
+func (d Hypergeometric) CDF(k int) float64 {
+	lo, hi := d.support()
+	if k >= hi {
+		return 1
+	}
+	if k-lo > hi-k {
+		return 1 - d.Survival(k)
+	}
+	sum := 0.0
+	for i := lo; i <= k; i++ {
+		sum += d.PMF(i)
+	}
+	return sum
+}
+
+func (d Hypergeometric) Survival(k int) float64 {
+	lo, hi := d.support()
+	if k < lo {
+		return 1
+	}
+	if k-lo < hi-k {
+		return 1 - d.CDF(k)
+	}
+	sum := 0.0
+	for i := k + 1; i <= hi; i++ {
+		sum += d.PMF(i)
+	}
+	return sum
+}
+
+func (d Hypergeometric) Quantile(p float64) int {
+	lo, hi := d.support()
+	return discreteQuantile(d.CDF, p, lo, hi)
+}
+
+func (d Hypergeometric) Mean() float64 {
+	return float64(d.Draws) * float64(d.Successes) / float64(d.Population)
+}
+
+func (d Hypergeometric) Variance() float64 {
+	n, k, pop := float64(d.Draws), float64(d.Successes), float64(d.Population)
+	return n * k / pop * (pop - k) / pop * (pop - n) / (pop - 1)
+}
+
+func (d Hypergeometric) Entropy() float64 { return discreteEntropy(d) }
+
+func (d Hypergeometric) Sample(rng RandomSource) int { return d.Quantile(rng.Float64()) }
+
+// standardNormal uses the Box–Muller transform, as LCG.NormalSample does.
+func standardNormal(rng RandomSource) float64 {
+	u1 := 1 - rng.Float64()
+	u2 := rng.Float64()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+// gammaVariate draws from Gamma(shape, 1) with the Marsaglia–Tsang method.
+func gammaVariate(rng RandomSource, shape float64) float64 {
+	if shape < 1 {
+		u := 1 - rng.Float64()
+		return gammaVariate(rng, shape+1) * math.Pow(u, 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := standardNormal(rng)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := 1 - rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// discreteQuantile finds the smallest k in [lo, hi] with cdf(k) >= p by
+// bisection; hi < lo means the support is unbounded above.
+func discreteQuantile(cdf func(int) float64, p float64, lo, hi int) int {
+	if p <= 0 {
+		return lo
+	}
+	if hi < lo {
+		if p >= 1 {
+			return math.MaxInt
+		}
+		step := 1
+		for hi = lo; cdf(hi) < p; hi += step {
+			lo = hi + 1
+			step *= 2
+		}
+	}
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if cdf(mid) >= p {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// discreteEntropy sums -p log p over the central mass, dropping tails below
+// roughly 1e-16.
+func discreteEntropy(d DiscreteDistribution) float64 {
+	h := 0.0
+	for k, hi := d.Quantile(1e-17), d.Quantile(1-1e-16); k <= hi; k++ {
+		if lp := d.LogPMF(k); !math.IsInf(lp, -1) {
+			h -= math.Exp(lp) * lp
+		}
+	}
+	return h
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
+
+func lbeta(a, b float64) float64 {
+	return lgamma(a) + lgamma(b) - lgamma(a+b)
+}
+
+func lchoose(n, k int) float64 {
+	return lgamma(float64(n+1)) - lgamma(float64(k+1)) - lgamma(float64(n-k+1))
+}
+
+func clampUnit(x float64) float64 {
+	return math.Min(math.Max(x, 0), 1)
+}
+
+// xlogy returns c*log(x) with the convention 0*log(0) = 0.
+func xlogy(c, x float64) float64 {
+	if c == 0 {
+		return 0
+	}
+	return c * math.Log(x)
+}
+
+func digamma(x float64) float64 {
+	if x <= 0 && x == math.Floor(x) {
+		return math.NaN()
+	}
+	if x < 0 {
+		return digamma(1-x) - math.Pi/math.Tan(math.Pi*x)
+	}
+	r := 0.0
+	for ; x < 10; x++ {
+		r -= 1 / x
+	}
+	f := 1 / (x * x)
+	return r + math.Log(x) - 0.5/x - f*(1.0/12-f*(1.0/120-f*(1.0/252-f*(1.0/240-f/132))))
+}
@@ -0,0 +1,487 @@
+// 2026 Update: Multi-Objective Optimization
+package optimization
+
+import (
+	"math"
+	"sort"
+)
+
+type MultiObjectiveFunc func(x []float64) []float64
+
+type ParetoSolution struct {
+	X        []float64
+	F        []float64
+	Rank     int
+	Crowding float64
+}
+
+func Dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+		if a[i] < b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+func NonDominatedSort(points [][]float64) [][]int {
+	n := len(points)
+	dominatedBy := make([][]int, n)
+	counts := make([]int, n)
+	fronts := [][]int{}
+	current := []int{}
+	for p := 0; p < n; p++ {
+		for q := 0; q < n; q++ {
+			if p == q {
+				continue
+			}
+			if Dominates(points[p], points[q]) {
+				dominatedBy[p] = append(dominatedBy[p], q)
+			} else if Dominates(points[q], points[p]) {
+				counts[p]++
+			}
+		}
+		if counts[p] == 0 {
+			current = append(current, p)
+		}
+	}
+	for len(current) > 0 {
+		fronts = append(fronts, current)
+		next := []int{}
+		for _, p := range current {
+			for _, q := range dominatedBy[p] {
+				counts[q]--
+				if counts[q] == 0 {
+					next = append(next, q)
+				}
+			}
+		}
+		sort.Ints(next)
+		current = next
+	}
+	return fronts
+}
+
+func ParetoFront(points [][]float64) []int {
+	front := []int{}
+	for i := range points {
+		dominated := false
+		for j := range points {
+			if i != j && Dominates(points[j], points[i]) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, i)
+		}
+	}
+	return front
+}
+
+func CrowdingDistance(points [][]float64, front []int) []float64 {
+	dist := make([]float64, len(front))
+	if len(front) == 0 {
+		return dist
+	}
+	if len(front) <= 2 {
+		for i := range dist {
+			dist[i] = math.Inf(1)
+		}
+		return dist
+	}
+	m := len(points[front[0]])
+	order := make([]int, len(front))
+	for obj := 0; obj < m; obj++ {
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return points[front[order[a]]][obj] < points[front[order[b]]][obj]
+		})
+		low := points[front[order[0]]][obj]
+		high := points[front[order[len(order)-1]]][obj]
+		dist[order[0]] = math.Inf(1)
+		dist[order[len(order)-1]] = math.Inf(1)
+		if high-low <= 0 {
+			continue
+		}
+		for k := 1; k < len(order)-1; k++ {
+			prev := points[front[order[k-1]]][obj]
+			next := points[front[order[k+1]]][obj]
+			dist[order[k]] += (next - prev) / (high - low)
+		}
+	}
+	return dist
+}
+
+func Hypervolume(points [][]float64, reference []float64) float64 {
+	inside := [][]float64{}
+	for _, p := range points {
+		ok := true
+		for i := range reference {
+			if p[i] >= reference[i] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			inside = append(inside, p)
+		}
+	}
+	return hypervolumeSlice(inside, reference, len(reference))
+}
+
+func hypervolumeSlice(points [][]float64, reference []float64, dims int) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	if dims == 1 {
+		low := points[0][0]
+		for _, p := range points {
+			low = math.Min(low, p[0])
+		}
+		return reference[0] - low
+	}
+	sorted := make([][]float64, len(points))
+	copy(sorted, points)
+	axis := dims - 1
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a][axis] < sorted[b][axis]
+	})
+	volume := 0.0
+	for i := range sorted {
+		upper := reference[axis]
+		if i+1 < len(sorted) {
+			upper = sorted[i+1][axis]
+		}
+		height := upper - sorted[i][axis]
+		if height <= 0 {
+			continue
+		}
+		volume += height * hypervolumeSlice(sorted[:i+1], reference, dims-1)
+	}
+	return volume
+}
+
+type NSGA2Settings struct {
+	Population    int
+	Generations   int
+	CrossoverRate float64
+	MutationRate  float64
+	EtaCrossover  float64
+	EtaMutation   float64
+	Seed          uint64
+	Workers       int
+}
+
+func DefaultNSGA2Settings(population, generations int) NSGA2Settings {
+	return NSGA2Settings{
+		Population:    population,
+		Generations:   generations,
+		CrossoverRate: 0.9,
+		MutationRate:  0,
+		EtaCrossover:  15,
+		EtaMutation:   20,
+		Seed:          42,
+		Workers:       0,
+	}
+}
+
+func evaluateObjectives(f MultiObjectiveFunc, pop [][]float64, workers int) [][]float64 {
+	values := make([][]float64, len(pop))
+	parallelFor(len(pop), workers, func(i int) {
+		values[i] = f(pop[i])
+	})
+	return values
+}
+
+func NSGA2(f MultiObjectiveFunc, dim int, bounds [][2]float64, settings NSGA2Settings) []ParetoSolution {
+	rng := NewRNG(settings.Seed)
+	n := settings.Population
+	if n%2 == 1 {
+		n++
+	}
+	mutation := settings.MutationRate
+	if mutation <= 0 {
+		mutation = 1 / float64(dim)
+	}
+	pop := make([][]float64, n)
+	for i := range pop {
+		pop[i] = randomVector(rng, dim, bounds)
+	}
+	objs := evaluateObjectives(f, pop, settings.Workers)
+	rank, crowd := nsgaRankCrowding(objs)
+	for gen := 0; gen < settings.Generations; gen++ {
+		offspring := make([][]float64, 0, n)
+		for len(offspring) < n {
+			p1 := pop[nsgaTournament(rng, rank, crowd)]
+			p2 := pop[nsgaTournament(rng, rank, crowd)]
+			c1, c2 := cloneVector(p1), cloneVector(p2)
+			if rng.Float64() < settings.CrossoverRate {
+				c1, c2 = sbxCrossover(rng, p1, p2, bounds, settings.EtaCrossover)
+			}
+			polynomialMutation(rng, c1, bounds, mutation, settings.EtaMutation)
+			polynomialMutation(rng, c2, bounds, mutation, settings.EtaMutation)
+			offspring = append(offspring, c1, c2)
+		}
+		childObjs := evaluateObjectives(f, offspring, settings.Workers)
+		combined := append(append([][]float64{}, pop...), offspring...)
+		combinedObjs := append(append([][]float64{}, objs...), childObjs...)
+		selected := nsgaSelect(combinedObjs, n)
+		pop = make([][]float64, n)
+		objs = make([][]float64, n)
+		for i, idx := range selected {
+			pop[i] = combined[idx]
+			objs[i] = combinedObjs[idx]
+		}
+		rank, crowd = nsgaRankCrowding(objs)
+	}
+	front := []ParetoSolution{}
+	for i := range pop {
+		if rank[i] == 0 {
+			front = append(front, ParetoSolution{X: pop[i], F: objs[i], Rank: 0, Crowding: crowd[i]})
+		}
+	}
+	return front
+}
+
+func nsgaRankCrowding(objs [][]float64) ([]int, []float64) {
+	rank := make([]int, len(objs))
+	crowd := make([]float64, len(objs))
+	for r, front := range NonDominatedSort(objs) {
+		dist := CrowdingDistance(objs, front)
+		for k, idx := range front {
+			rank[idx] = r
+			crowd[idx] = dist[k]
+		}
+	}
+	return rank, crowd
+}
+
+func nsgaSelect(objs [][]float64, n int) []int {
+	selected := make([]int, 0, n)
+	for _, front := range NonDominatedSort(objs) {
+		if len(selected)+len(front) <= n {
+			selected = append(selected, front...)
+			continue
+		}
+		dist := CrowdingDistance(objs, front)
+		order := make([]int, len(front))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return dist[order[a]] > dist[order[b]]
+		})
+		for _, k := range order[:n-len(selected)] {
+			selected = append(selected, front[k])
+		}
+		break
+	}
+	return selected
+}
+
+func nsgaTournament(rng *RNG, rank []int, crowd []float64) int {
+	a := rng.Intn(len(rank))
+	b := rng.Intn(len(rank))
+	if rank[a] != rank[b] {
+		if rank[a] < rank[b] {
+			return a
+		}
+		return b
+	}
+	if crowd[b] > crowd[a] {
+		return b
+	}
+	return a
+}
+
+func sbxCrossover(rng *RNG, a, b []float64, bounds [][2]float64, eta float64) ([]float64, []float64) {
+	c1 := cloneVector(a)
+	c2 := cloneVector(b)
+	for i := range a {
+		if rng.Float64() > 0.5 || absO(a[i]-b[i]) < 1e-14 {
+			continue
+		}
+		u := rng.Float64()
+		beta := math.Pow(2*u, 1/(eta+1))
+		if u > 0.5 {
+			beta = math.Pow(1/(2*(1-u)), 1/(eta+1))
+		}
+		c1[i] = clampValue(0.5*((1+beta)*a[i]+(1-beta)*b[i]), bounds[i][0], bounds[i][1])
+		c2[i] = clampValue(0.5*((1-beta)*a[i]+(1+beta)*b[i]), bounds[i][0], bounds[i][1])
+	}
+	return c1, c2
+}
+
+func polynomialMutation(rng *RNG, x []float64, bounds [][2]float64, rate, eta float64) {
+	for i := range x {
+		if rng.Float64() >= rate {
+			continue
+		}
+		span := bounds[i][1] - bounds[i][0]
+		if span <= 0 {
+			continue
+		}
+		u := rng.Float64()
+		delta := math.Pow(2*u, 1/(eta+1)) - 1
+		if u >= 0.5 {
+			delta = 1 - math.Pow(2*(1-u), 1/(eta+1))
+		}
+		x[i] = clampValue(x[i]+delta*span, bounds[i][0], bounds[i][1])
+	}
+}
+
+type MOEADSettings struct {
+	Population    int
+	Generations   int
+	Neighbors     int
+	CrossoverRate float64
+	MutationRate  float64
+	EtaCrossover  float64
+	EtaMutation   float64
+	Seed          uint64
+}
+
+func DefaultMOEADSettings(population, generations int) MOEADSettings {
+	return MOEADSettings{
+		Population:    population,
+		Generations:   generations,
+		Neighbors:     20,
+		CrossoverRate: 0.9,
+		MutationRate:  0,
+		EtaCrossover:  20,
+		EtaMutation:   20,
+		Seed:          42,
+	}
+}
+
+// MOEAD minimises f with MOEA/D (Zhang & Li, 2007): one Tchebycheff
+// subproblem per weight vector, each mating with and replacing among its
+// Neighbors nearest subproblems. The weights are SimplexLatticeWeights(m,
+// Population) for m objectives, so the population is the lattice size, which
+// is usually below Population and can exceed it when Population < m.
+func MOEAD(f MultiObjectiveFunc, dim int, bounds [][2]float64, settings MOEADSettings) []ParetoSolution {
+	rng := NewRNG(settings.Seed)
+	mutation := settings.MutationRate
+	if mutation <= 0 {
+		mutation = 1 / float64(dim)
+	}
+	first := randomVector(rng, dim, bounds)
+	firstObj := f(first)
+	weights := SimplexLatticeWeights(len(firstObj), settings.Population)
+	n := len(weights)
+	neighbors := settings.Neighbors
+	if neighbors < 2 {
+		neighbors = 2
+	}
+	if neighbors > n {
+		neighbors = n
+	}
+	hood := make([][]int, n)
+	for i := range weights {
+		dist := make([]float64, n)
+		for j := range weights {
+			dist[j] = vecNorm(subVectors(weights[i], weights[j]))
+		}
+		hood[i] = argsort(dist)[:neighbors]
+	}
+	pop := make([][]float64, n)
+	objs := make([][]float64, n)
+	pop[0], objs[0] = first, firstObj
+	for i := 1; i < n; i++ {
+		pop[i] = randomVector(rng, dim, bounds)
+		objs[i] = f(pop[i])
+	}
+	ideal := cloneVector(firstObj)
+	for _, o := range objs {
+		for k := range ideal {
+			ideal[k] = math.Min(ideal[k], o[k])
+		}
+	}
+	for gen := 0; gen < settings.Generations; gen++ {
+		for i := 0; i < n; i++ {
+			a := hood[i][rng.Intn(neighbors)]
+			b := hood[i][rng.Intn(neighbors)]
+			child := cloneVector(pop[a])
+			if rng.Float64() < settings.CrossoverRate {
+				child, _ = sbxCrossover(rng, pop[a], pop[b], bounds, settings.EtaCrossover)
+			}
+			polynomialMutation(rng, child, bounds, mutation, settings.EtaMutation)
+			childObj := f(child)
+			for k := range ideal {
+				ideal[k] = math.Min(ideal[k], childObj[k])
+			}
+			for _, j := range hood[i] {
+				if tchebycheff(childObj, weights[j], ideal) <= tchebycheff(objs[j], weights[j], ideal) {
+					pop[j] = child
+					objs[j] = childObj
+				}
+			}
+		}
+	}
+	front := []ParetoSolution{}
+	for _, idx := range ParetoFront(objs) {
+		front = append(front, ParetoSolution{X: pop[idx], F: objs[idx], Rank: 0})
+	}
+	return front
+}
+
+func tchebycheff(obj, weight, ideal []float64) float64 {
+	worst := 0.0
+	for k := range obj {
+		w := weight[k]
+		if w < 1e-6 {
+			w = 1e-6
+		}
+		worst = math.Max(worst, w*absO(obj[k]-ideal[k]))
+	}
+	return worst
+}
+
+// SimplexLatticeWeights returns the Das–Dennis weight vectors k/H summing to
+// one, with the largest number of divisions H whose C(H+m-1, m-1) points fit
+// in maxCount. H is at least 1, so the m unit vectors are always returned even
+// when maxCount < m.
+func SimplexLatticeWeights(objectives, maxCount int) [][]float64 {
+	if objectives == 1 {
+		return [][]float64{{1}}
+	}
+	divisions := 1
+	for latticeSize(divisions+1, objectives) <= maxCount {
+		divisions++
+	}
+	weights := [][]float64{}
+	current := make([]int, objectives)
+	var fill func(k, remaining int)
+	fill = func(k, remaining int) {
+		if k == objectives-1 {
+			current[k] = remaining
+			w := make([]float64, objectives)
+			for i := range w {
+				w[i] = float64(current[i]) / float64(divisions)
+			}
+			weights = append(weights, w)
+			return
+		}
+		for v := 0; v <= remaining; v++ {
+			current[k] = v
+			fill(k+1, remaining-v)
+		}
+	}
+	fill(0, divisions)
+	return weights
+}
+
+func latticeSize(divisions, objectives int) int {
+	size := 1.0
+	for i := 1; i < objectives; i++ {
+		size = size * float64(divisions+i) / float64(i)
+	}
+	return int(math.Round(size))
+}
@@ -0,0 +1,483 @@
+// 2026 Update: Combinatorial Encodings
+package optimization
+
+import "math"
+
+type CombinatorialProblem[S any] interface {
+	Cost(s S) float64
+	Random(rng *RNG) S
+	Neighbor(rng *RNG, s S) S
+	Crossover(rng *RNG, a, b S) (S, S)
+	Mutate(rng *RNG, s S) S
+	Clone(s S) S
+	Equal(a, b S) bool
+}
+
+type Permutation []int
+
+type BitString []bool
+
+type PermutationCrossover int
+
+const (
+	OrderCrossoverKind PermutationCrossover = iota
+	PMXCrossoverKind
+)
+
+type PermutationMutation int
+
+const (
+	SwapMutationKind PermutationMutation = iota
+	InversionMutationKind
+)
+
+type PermutationNeighborhood int
+
+const (
+	TwoOptNeighborhood PermutationNeighborhood = iota
+	SwapNeighborhood
+)
+
+type PermutationProblem struct {
+	Size          int
+	CostFunc      func(p Permutation) float64
+	CrossoverKind PermutationCrossover
+	MutationKind  PermutationMutation
+	NeighborKind  PermutationNeighborhood
+}
+
+func (p PermutationProblem) Cost(s Permutation) float64 {
+	return p.CostFunc(s)
+}
+
+func (p PermutationProblem) Random(rng *RNG) Permutation {
+	return RandomPermutation(rng, p.Size)
+}
+
+func (p PermutationProblem) Neighbor(rng *RNG, s Permutation) Permutation {
+	if len(s) < 2 {
+		return p.Clone(s)
+	}
+	i := rng.Intn(len(s))
+	j := rng.Intn(len(s) - 1)
+	if j >= i {
+		j++
+	}
+	if p.NeighborKind == SwapNeighborhood {
+		out := p.Clone(s)
+		out[i], out[j] = out[j], out[i]
+		return out
+	}
+	return TwoOptMove(s, i, j)
+}
+
+func (p PermutationProblem) Crossover(rng *RNG, a, b Permutation) (Permutation, Permutation) {
+	if p.CrossoverKind == PMXCrossoverKind {
+		return PMXCrossover(rng, a, b)
+	}
+	return OrderCrossover(rng, a, b)
+}
+
+func (p PermutationProblem) Mutate(rng *RNG, s Permutation) Permutation {
+	if p.MutationKind == InversionMutationKind {
+		return InversionMutation(rng, s)
+	}
+	return SwapMutation(rng, s)
+}
+
+func (p PermutationProblem) Clone(s Permutation) Permutation {
+	out := make(Permutation, len(s))
+	copy(out, s)
+	return out
+}
+
+func (p PermutationProblem) Equal(a, b Permutation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func RandomPermutation(rng *RNG, n int) Permutation {
+	p := make(Permutation, n)
+	for i := range p {
+		p[i] = i
+	}
+	for i := n - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		p[i], p[j] = p[j], p[i]
+	}
+	return p
+}
+
+func randomCutPoints(rng *RNG, n int) (int, int) {
+	i := rng.Intn(n)
+	j := rng.Intn(n)
+	if i > j {
+		i, j = j, i
+	}
+	return i, j
+}
+
+func OrderCrossover(rng *RNG, a, b Permutation) (Permutation, Permutation) {
+	lo, hi := randomCutPoints(rng, len(a))
+	return orderChild(a, b, lo, hi), orderChild(b, a, lo, hi)
+}
+
+func orderChild(keep, fill Permutation, lo, hi int) Permutation {
+	n := len(keep)
+	child := make(Permutation, n)
+	used := make(map[int]bool, hi-lo+1)
+	for i := lo; i <= hi; i++ {
+		child[i] = keep[i]
+		used[keep[i]] = true
+	}
+	pos := (hi + 1) % n
+	for k := 0; k < n; k++ {
+		gene := fill[(hi+1+k)%n]
+		if used[gene] {
+			continue
+		}
+		child[pos] = gene
+		pos = (pos + 1) % n
+	}
+	return child
+}
+
+func PMXCrossover(rng *RNG, a, b Permutation) (Permutation, Permutation) {
+	lo, hi := randomCutPoints(rng, len(a))
+	return pmxChild(a, b, lo, hi), pmxChild(b, a, lo, hi)
+}
+
+func pmxChild(keep, fill Permutation, lo, hi int) Permutation {
+	n := len(keep)
+	child := make(Permutation, n)
+	for i := range child {
+		child[i] = -1
+	}
+	where := make(map[int]int, n)
+	for i, gene := range fill {
+		where[gene] = i
+	}
+	for i := lo; i <= hi; i++ {
+		child[i] = keep[i]
+	}
+	for i := lo; i <= hi; i++ {
+		gene := fill[i]
+		if pmxContains(child[lo:hi+1], gene) {
+			continue
+		}
+		pos := i
+		for pos >= lo && pos <= hi {
+			pos = where[keep[pos]]
+		}
+		child[pos] = gene
+	}
+	for i := range child {
+		if child[i] == -1 {
+			child[i] = fill[i]
+		}
+	}
+	return child
+}
+
+func pmxContains(segment Permutation, gene int) bool {
+	for _, g := range segment {
+		if g == gene {
+			return true
+		}
+	}
+	return false
+}
+
+func SwapMutation(rng *RNG, p Permutation) Permutation {
+	out := make(Permutation, len(p))
+	copy(out, p)
+	if len(out) < 2 {
+		return out
+	}
+	i, j := randomCutPoints(rng, len(out))
+	out[i], out[j] = out[j], out[i]
+	return out
+}
+
+func InversionMutation(rng *RNG, p Permutation) Permutation {
+	i, j := randomCutPoints(rng, len(p))
+	return TwoOptMove(p, i, j)
+}
+
+func TwoOptMove(p Permutation, i, j int) Permutation {
+	if i > j {
+		i, j = j, i
+	}
+	out := make(Permutation, len(p))
+	copy(out, p)
+	for lo, hi := i, j; lo < hi; lo, hi = lo+1, hi-1 {
+		out[lo], out[hi] = out[hi], out[lo]
+	}
+	return out
+}
+
+func TwoOptLocalSearch(p Permutation, cost func(Permutation) float64) Permutation {
+	best := TwoOptMove(p, 0, 0)
+	bestVal := cost(best)
+	improved := true
+	for improved {
+		improved = false
+		for i := 0; i < len(best)-1; i++ {
+			for j := i + 1; j < len(best); j++ {
+				cand := TwoOptMove(best, i, j)
+				val := cost(cand)
+				if val < bestVal-1e-12 {
+					best, bestVal = cand, val
+					improved = true
+				}
+			}
+		}
+	}
+	return best
+}
+
+func TourLength(dist [][]float64) func(Permutation) float64 {
+	return func(p Permutation) float64 {
+		total := 0.0
+		for i := range p {
+			total += dist[p[i]][p[(i+1)%len(p)]]
+		}
+		return total
+	}
+}
+
+type BitStringProblem struct {
+	Size     int
+	CostFunc func(b BitString) float64
+	FlipRate float64
+}
+
+func (p BitStringProblem) Cost(s BitString) float64 {
+	return p.CostFunc(s)
+}
+
+func (p BitStringProblem) Random(rng *RNG) BitString {
+	out := make(BitString, p.Size)
+	for i := range out {
+		out[i] = rng.Float64() < 0.5
+	}
+	return out
+}
+
+func (p BitStringProblem) Neighbor(rng *RNG, s BitString) BitString {
+	out := p.Clone(s)
+	if len(out) > 0 {
+		idx := rng.Intn(len(out))
+		out[idx] = !out[idx]
+	}
+	return out
+}
+
+func (p BitStringProblem) Crossover(rng *RNG, a, b BitString) (BitString, BitString) {
+	return UniformBitCrossover(rng, a, b)
+}
+
+func (p BitStringProblem) Mutate(rng *RNG, s BitString) BitString {
+	rate := p.FlipRate
+	if rate <= 0 && len(s) > 0 {
+		rate = 1 / float64(len(s))
+	}
+	return BitFlipMutation(rng, s, rate)
+}
+
+func (p BitStringProblem) Clone(s BitString) BitString {
+	out := make(BitString, len(s))
+	copy(out, s)
+	return out
+}
+
+func (p BitStringProblem) Equal(a, b BitString) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func UniformBitCrossover(rng *RNG, a, b BitString) (BitString, BitString) {
+	c1 := make(BitString, len(a))
+	c2 := make(BitString, len(a))
+	for i := range a {
+		if rng.Float64() < 0.5 {
+			c1[i], c2[i] = a[i], b[i]
+		} else {
+			c1[i], c2[i] = b[i], a[i]
+		}
+	}
+	return c1, c2
+}
+
+func BitFlipMutation(rng *RNG, b BitString, rate float64) BitString {
+	out := make(BitString, len(b))
+	copy(out, b)
+	for i := range out {
+		if rng.Float64() < rate {
+			out[i] = !out[i]
+		}
+	}
+	return out
+}
+
+func KnapsackCost(values, weights []float64, capacity float64) func(BitString) float64 {
+	penalty := 0.0
+	for _, v := range values {
+		penalty += absO(v)
+	}
+	return func(b BitString) float64 {
+		value, weight := 0.0, 0.0
+		for i, take := range b {
+			if take {
+				value += values[i]
+				weight += weights[i]
+			}
+		}
+		if weight > capacity {
+			return -value + (1+penalty)*(weight-capacity)
+		}
+		return -value
+	}
+}
+
+func SimulatedAnnealingProblem[S any](p CombinatorialProblem[S], x0 S, settings AnnealSettings) S {
+	rng := NewRNG(settings.Seed)
+	x := p.Clone(x0)
+	currentVal := p.Cost(x)
+	best := p.Clone(x)
+	bestVal := currentVal
+	temp := settings.InitialTemp
+	for temp > settings.MinTemp {
+		for i := 0; i < settings.Iterations; i++ {
+			cand := p.Neighbor(rng, x)
+			val := p.Cost(cand)
+			delta := val - currentVal
+			if delta < 0 || rng.Float64() < math.Exp(-delta/temp) {
+				x = cand
+				currentVal = val
+				if val < bestVal {
+					bestVal = val
+					best = p.Clone(cand)
+				}
+			}
+		}
+		temp *= settings.Alpha
+	}
+	return best
+}
+
+func GeneticAlgorithmProblem[S any](p CombinatorialProblem[S], settings GASettings) S {
+	rng := NewRNG(settings.Seed)
+	pop := make([]S, settings.Population)
+	for i := range pop {
+		pop[i] = p.Random(rng)
+	}
+	fitness := evaluateProblem(p, pop, settings.Workers)
+	for gen := 0; gen < settings.Generations; gen++ {
+		newPop := make([]S, 0, settings.Population)
+		for e := 0; e < settings.Elitism; e++ {
+			bestIdx := argmin(fitness)
+			newPop = append(newPop, p.Clone(pop[bestIdx]))
+			fitness[bestIdx] = math.Inf(1)
+		}
+		for len(newPop) < settings.Population {
+			p1 := pop[tournamentIndex(rng, fitness, settings.Tournament)]
+			p2 := pop[tournamentIndex(rng, fitness, settings.Tournament)]
+			child1, child2 := p.Clone(p1), p.Clone(p2)
+			if rng.Float64() < settings.CrossoverRate {
+				child1, child2 = p.Crossover(rng, p1, p2)
+			}
+			if rng.Float64() < settings.MutationRate {
+				child1 = p.Mutate(rng, child1)
+			}
+			if rng.Float64() < settings.MutationRate {
+				child2 = p.Mutate(rng, child2)
+			}
+			newPop = append(newPop, child1)
+			if len(newPop) < settings.Population {
+				newPop = append(newPop, child2)
+			}
+		}
+		pop = newPop
+		fitness = evaluateProblem(p, pop, settings.Workers)
+	}
+	return pop[argmin(fitness)]
+}
+
+func evaluateProblem[S any](p CombinatorialProblem[S], pop []S, workers int) []float64 {
+	values := make([]float64, len(pop))
+	parallelFor(len(pop), workers, func(i int) {
+		values[i] = p.Cost(pop[i])
+	})
+	return values
+}
+
+func tournamentIndex(rng *RNG, fitness []float64, k int) int {
+	best := rng.Intn(len(fitness))
+	for i := 1; i < k; i++ {
+		idx := rng.Intn(len(fitness))
+		if fitness[idx] < fitness[best] {
+			best = idx
+		}
+	}
+	return best
+}
+
+func TabuSearchProblem[S any](p CombinatorialProblem[S], x0 S, settings TabuSettings) S {
+	rng := NewRNG(settings.Seed)
+	x := p.Clone(x0)
+	best := p.Clone(x)
+	bestVal := p.Cost(best)
+	mem := make([]S, 0, settings.TabuSize+1)
+	for iter := 0; iter < settings.Iterations; iter++ {
+		found := false
+		var selected S
+		selectedVal := math.Inf(1)
+		for i := 0; i < tabuCandidates; i++ {
+			cand := p.Neighbor(rng, x)
+			val := p.Cost(cand)
+			if val >= selectedVal {
+				continue
+			}
+			if val < bestVal || !isTabuProblem(p, cand, mem) {
+				selected, selectedVal, found = cand, val, true
+			}
+		}
+		if !found {
+			continue
+		}
+		x = selected
+		if selectedVal < bestVal {
+			bestVal = selectedVal
+			best = p.Clone(selected)
+		}
+		mem = append(mem, p.Clone(selected))
+		if len(mem) > settings.TabuSize {
+			mem = mem[1:]
+		}
+	}
+	return best
+}
+
+func isTabuProblem[S any](p CombinatorialProblem[S], x S, tabu []S) bool {
+	for _, t := range tabu {
+		if p.Equal(x, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,392 @@
+// 2026 Update: Bayesian Optimization
+package optimization
+
+import "math"
+
+type Kernel interface {
+	Eval(a, b []float64) float64
+	LogParams() []float64
+	WithLogParams(p []float64) Kernel
+}
+
+type RBFKernel struct {
+	LengthScale float64
+	Variance    float64
+}
+
+func (k RBFKernel) Eval(a, b []float64) float64 {
+	r2 := squaredDistance(a, b)
+	return k.Variance * math.Exp(-0.5*r2/(k.LengthScale*k.LengthScale))
+}
+
+func (k RBFKernel) LogParams() []float64 {
+	return []float64{math.Log(k.LengthScale), math.Log(k.Variance)}
+}
+
+func (k RBFKernel) WithLogParams(p []float64) Kernel {
+	return RBFKernel{LengthScale: math.Exp(p[0]), Variance: math.Exp(p[1])}
+}
+
+type MaternKernel struct {
+	Nu          float64
+	LengthScale float64
+	Variance    float64
+}
+
+func (k MaternKernel) Eval(a, b []float64) float64 {
+	r := math.Sqrt(squaredDistance(a, b)) / k.LengthScale
+	switch {
+	case k.Nu <= 0.5:
+		return k.Variance * math.Exp(-r)
+	case k.Nu <= 1.5:
+		s := math.Sqrt(3) * r
+		return k.Variance * (1 + s) * math.Exp(-s)
+	default:
+		s := math.Sqrt(5) * r
+		return k.Variance * (1 + s + s*s/3) * math.Exp(-s)
+	}
+}
+
+func (k MaternKernel) LogParams() []float64 {
+	return []float64{math.Log(k.LengthScale), math.Log(k.Variance)}
+}
+
+func (k MaternKernel) WithLogParams(p []float64) Kernel {
+	return MaternKernel{Nu: k.Nu, LengthScale: math.Exp(p[0]), Variance: math.Exp(p[1])}
+}
+
+func squaredDistance(a, b []float64) float64 {
+	s := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		s += d * d
+	}
+	return s
+}
+
+type GaussianProcess struct {
+	Kernel Kernel
+	Noise  float64
+	X      [][]float64
+	Y      []float64
+	yMean  float64
+	yScale float64
+	chol   [][]float64
+	alpha  []float64
+}
+
+func NewGaussianProcess(kernel Kernel, noise float64) *GaussianProcess {
+	return &GaussianProcess{Kernel: kernel, Noise: noise}
+}
+
+func (gp *GaussianProcess) Fit(X [][]float64, y []float64) bool {
+	gp.X = X
+	gp.Y = y
+	n := len(y)
+	gp.yMean = 0
+	for _, v := range y {
+		gp.yMean += v
+	}
+	if n > 0 {
+		gp.yMean /= float64(n)
+	}
+	gp.yScale = 0
+	for _, v := range y {
+		gp.yScale += (v - gp.yMean) * (v - gp.yMean)
+	}
+	if n > 1 {
+		gp.yScale = math.Sqrt(gp.yScale / float64(n-1))
+	}
+	if gp.yScale < 1e-12 {
+		gp.yScale = 1
+	}
+	K := make([][]float64, n)
+	for i := range K {
+		K[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			K[i][j] = gp.Kernel.Eval(X[i], X[j])
+			K[j][i] = K[i][j]
+		}
+	}
+	jitter := gp.Noise
+	for attempt := 0; attempt < 8; attempt++ {
+		for i := range K {
+			K[i][i] += jitter
+		}
+		L, ok := choleskyFactor(K)
+		for i := range K {
+			K[i][i] -= jitter
+		}
+		if ok {
+			gp.chol = L
+			scaled := make([]float64, n)
+			for i, v := range y {
+				scaled[i] = (v - gp.yMean) / gp.yScale
+			}
+			gp.alpha = choleskySolve(L, scaled)
+			return true
+		}
+		jitter = math.Max(jitter*10, 1e-10)
+	}
+	gp.chol = nil
+	gp.alpha = nil
+	return false
+}
+
+func (gp *GaussianProcess) Predict(x []float64) (float64, float64) {
+	if gp.chol == nil {
+		return gp.yMean, gp.Kernel.Eval(x, x) * gp.yScale * gp.yScale
+	}
+	k := make([]float64, len(gp.X))
+	for i := range gp.X {
+		k[i] = gp.Kernel.Eval(x, gp.X[i])
+	}
+	mean := dotProd(k, gp.alpha)
+	v := forwardSubstitute(gp.chol, k)
+	variance := gp.Kernel.Eval(x, x) - dotProd(v, v)
+	if variance < 1e-15 {
+		variance = 1e-15
+	}
+	return gp.yMean + gp.yScale*mean, variance * gp.yScale * gp.yScale
+}
+
+func (gp *GaussianProcess) LogMarginalLikelihood() float64 {
+	if gp.chol == nil {
+		return math.Inf(-1)
+	}
+	n := len(gp.Y)
+	fit := 0.0
+	for i, v := range gp.Y {
+		fit += (v - gp.yMean) / gp.yScale * gp.alpha[i]
+	}
+	logDet := 0.0
+	for i := 0; i < n; i++ {
+		logDet += math.Log(gp.chol[i][i])
+	}
+	return -0.5*fit - logDet - 0.5*float64(n)*math.Log(2*math.Pi)
+}
+
+func (gp *GaussianProcess) OptimizeHyperparameters(X [][]float64, y []float64, restarts int, seed uint64) float64 {
+	rng := NewRNG(seed)
+	base := append(gp.Kernel.LogParams(), math.Log(math.Max(gp.Noise, 1e-10)))
+	objective := func(p []float64) float64 {
+		for _, v := range p {
+			if v < -12 || v > 8 {
+				return 1e10
+			}
+		}
+		trial := &GaussianProcess{Kernel: gp.Kernel.WithLogParams(p[:len(p)-1]), Noise: math.Exp(p[len(p)-1])}
+		if !trial.Fit(X, y) {
+			return 1e10
+		}
+		return -trial.LogMarginalLikelihood()
+	}
+	settings := DefaultNelderMeadSettings()
+	settings.MaxIter = 300
+	best := NelderMeadWithSettings(objective, base, settings)
+	bestVal := objective(best)
+	for r := 0; r < restarts; r++ {
+		start := make([]float64, len(base))
+		for i := range start {
+			start[i] = base[i] + rng.Range(-2, 2)
+		}
+		cand := NelderMeadWithSettings(objective, start, settings)
+		if val := objective(cand); val < bestVal {
+			best, bestVal = cand, val
+		}
+	}
+	gp.Kernel = gp.Kernel.WithLogParams(best[:len(best)-1])
+	gp.Noise = math.Exp(best[len(best)-1])
+	gp.Fit(X, y)
+	return gp.LogMarginalLikelihood()
+}
+
+func choleskyFactor(A [][]float64) ([][]float64, bool) {
+	n := len(A)
+	L := make([][]float64, n)
+	for i := range L {
+		L[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := A[i][j]
+			for k := 0; k < j; k++ {
+				sum -= L[i][k] * L[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, false
+				}
+				L[i][i] = math.Sqrt(sum)
+			} else {
+				L[i][j] = sum / L[j][j]
+			}
+		}
+	}
+	return L, true
+}
+
+func forwardSubstitute(L [][]float64, b []float64) []float64 {
+	n := len(b)
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= L[i][k] * x[k]
+		}
+		x[i] = sum / L[i][i]
+	}
+	return x
+}
+
+func backSubstituteTransposed(L [][]float64, b []float64) []float64 {
+	n := len(b)
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := b[i]
+		for k := i + 1; k < n; k++ {
+			sum -= L[k][i] * x[k]
+		}
+		x[i] = sum / L[i][i]
+	}
+	return x
+}
+
+func choleskySolve(L [][]float64, b []float64) []float64 {
+	return backSubstituteTransposed(L, forwardSubstitute(L, b))
+}
+
+type Acquisition int
+
+const (
+	ExpectedImprovement Acquisition = iota
+	UpperConfidenceBound
+	ProbabilityOfImprovement
+)
+
+func AcquisitionValue(kind Acquisition, mean, variance, best, xi, kappa float64) float64 {
+	std := math.Sqrt(variance)
+	switch kind {
+	case UpperConfidenceBound:
+		return -(mean - kappa*std)
+	case ProbabilityOfImprovement:
+		return normalCDF((best - mean - xi) / std)
+	default:
+		improvement := best - mean - xi
+		z := improvement / std
+		return improvement*normalCDF(z) + std*normalPDF(z)
+	}
+}
+
+func normalPDF(z float64) float64 {
+	return math.Exp(-0.5*z*z) / math.Sqrt(2*math.Pi)
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+type BayesOptSettings struct {
+	InitialPoints int
+	Iterations    int
+	Acquisition   Acquisition
+	Xi            float64
+	Kappa         float64
+	Kernel        Kernel
+	Noise         float64
+	FitHyper      bool
+	Candidates    int
+	Seed          uint64
+}
+
+func DefaultBayesOptSettings() BayesOptSettings {
+	return BayesOptSettings{
+		InitialPoints: 5,
+		Iterations:    25,
+		Acquisition:   ExpectedImprovement,
+		Xi:            0.01,
+		Kappa:         2.0,
+		Kernel:        MaternKernel{Nu: 2.5, LengthScale: 0.3, Variance: 1},
+		Noise:         1e-6,
+		FitHyper:      true,
+		Candidates:    500,
+		Seed:          42,
+	}
+}
+
+func BayesianOptimization(f ObjectiveFunc, bounds [][2]float64, settings BayesOptSettings) []float64 {
+	rng := NewRNG(settings.Seed)
+	dim := len(bounds)
+	unit := make([][2]float64, dim)
+	for i := range unit {
+		unit[i] = [2]float64{0, 1}
+	}
+	toBounds := func(u []float64) []float64 {
+		x := make([]float64, dim)
+		for i := range u {
+			x[i] = bounds[i][0] + u[i]*(bounds[i][1]-bounds[i][0])
+		}
+		return x
+	}
+	kernel := settings.Kernel
+	if kernel == nil {
+		kernel = DefaultBayesOptSettings().Kernel
+	}
+	gp := NewGaussianProcess(kernel, settings.Noise)
+	X := [][]float64{}
+	y := []float64{}
+	bestIdx := 0
+	observe := func(u []float64) {
+		X = append(X, u)
+		y = append(y, f(toBounds(u)))
+		if y[len(y)-1] < y[bestIdx] {
+			bestIdx = len(y) - 1
+		}
+	}
+	initial := settings.InitialPoints
+	if initial < 1 {
+		initial = 1
+	}
+	strata := make([]Permutation, dim)
+	for j := range strata {
+		strata[j] = RandomPermutation(rng, initial)
+	}
+	for i := 0; i < initial; i++ {
+		u := make([]float64, dim)
+		for j := range u {
+			u[j] = (float64(strata[j][i]) + rng.Float64()) / float64(initial)
+		}
+		observe(u)
+	}
+	for iter := 0; iter < settings.Iterations; iter++ {
+		if settings.FitHyper {
+			gp.OptimizeHyperparameters(X, y, 2, rng.Next())
+		} else {
+			gp.Fit(X, y)
+		}
+		best := y[bestIdx]
+		score := func(u []float64) float64 {
+			mean, variance := gp.Predict(u)
+			return -AcquisitionValue(settings.Acquisition, mean, variance, best, settings.Xi, settings.Kappa)
+		}
+		candidate := X[bestIdx]
+		candidateScore := score(candidate)
+		for c := 0; c < settings.Candidates; c++ {
+			u := randomVector(rng, dim, unit)
+			if s := score(u); s < candidateScore {
+				candidate, candidateScore = u, s
+			}
+		}
+		polish := DefaultNelderMeadSettings()
+		polish.MaxIter = 200
+		polish.SimplexStep = 0.05
+		refined := clampVector(NelderMeadWithSettings(func(u []float64) float64 {
+			return score(clampVector(u, unit))
+		}, candidate, polish), unit)
+		if score(refined) <= candidateScore {
+			candidate = refined
+		}
+		observe(candidate)
+	}
+	return toBounds(X[bestIdx])
+}
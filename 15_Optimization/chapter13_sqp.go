@@ -0,0 +1,395 @@
+// 2026 Update: Sequential Quadratic Programming
+package optimization
+
+import "math"
+
+type SQPProblem struct {
+	F    ObjectiveFunc
+	Grad func([]float64) []float64
+	// Equality constraints h(x) = 0 and inequality constraints g(x) <= 0.
+	// Nil gradient slices fall back to finite differences.
+	Eq       []ObjectiveFunc
+	EqGrad   []func([]float64) []float64
+	Ineq     []ObjectiveFunc
+	IneqGrad []func([]float64) []float64
+	Bounds   [][2]float64
+}
+
+type SQPSettings struct {
+	MaxIter      int
+	Tol          float64
+	LineC1       float64
+	LineTau      float64
+	MinStep      float64
+	DiffStep     float64
+	QPIterations int
+}
+
+func DefaultSQPSettings() SQPSettings {
+	return SQPSettings{
+		MaxIter:      200,
+		Tol:          1e-8,
+		LineC1:       1e-4,
+		LineTau:      0.5,
+		MinStep:      1e-10,
+		DiffStep:     1e-7,
+		QPIterations: 100,
+	}
+}
+
+type SQPResult struct {
+	X            []float64
+	Value        float64
+	LambdaEq     []float64
+	LambdaIneq   []float64
+	LambdaLower  []float64
+	LambdaUpper  []float64
+	KKTViolation float64
+	Iterations   int
+	Converged    bool
+}
+
+type sqpPoint struct {
+	f     float64
+	grad  []float64
+	cEq   []float64
+	aEq   [][]float64
+	cIneq []float64
+	aIneq [][]float64
+}
+
+func sqpEvaluate(p SQPProblem, x []float64, h float64) sqpPoint {
+	n := len(x)
+	pt := sqpPoint{f: p.F(x)}
+	if p.Grad != nil {
+		pt.grad = p.Grad(x)
+	} else {
+		pt.grad = FiniteDifferenceGrad(p.F, x, h)
+	}
+	for i, c := range p.Eq {
+		pt.cEq = append(pt.cEq, c(x))
+		if i < len(p.EqGrad) && p.EqGrad[i] != nil {
+			pt.aEq = append(pt.aEq, p.EqGrad[i](x))
+		} else {
+			pt.aEq = append(pt.aEq, FiniteDifferenceGrad(c, x, h))
+		}
+	}
+	for i, c := range p.Ineq {
+		pt.cIneq = append(pt.cIneq, c(x))
+		if i < len(p.IneqGrad) && p.IneqGrad[i] != nil {
+			pt.aIneq = append(pt.aIneq, p.IneqGrad[i](x))
+		} else {
+			pt.aIneq = append(pt.aIneq, FiniteDifferenceGrad(c, x, h))
+		}
+	}
+	if len(p.Bounds) == n {
+		for j := 0; j < n; j++ {
+			if !math.IsInf(p.Bounds[j][0], -1) {
+				row := make([]float64, n)
+				row[j] = -1
+				pt.cIneq = append(pt.cIneq, p.Bounds[j][0]-x[j])
+				pt.aIneq = append(pt.aIneq, row)
+			}
+			if !math.IsInf(p.Bounds[j][1], 1) {
+				row := make([]float64, n)
+				row[j] = 1
+				pt.cIneq = append(pt.cIneq, x[j]-p.Bounds[j][1])
+				pt.aIneq = append(pt.aIneq, row)
+			}
+		}
+	}
+	return pt
+}
+
+func sqpLagrangianGrad(pt sqpPoint, lambdaEq, lambdaIneq []float64) []float64 {
+	g := cloneVector(pt.grad)
+	for i, row := range pt.aEq {
+		for j := range g {
+			g[j] += lambdaEq[i] * row[j]
+		}
+	}
+	for i, row := range pt.aIneq {
+		for j := range g {
+			g[j] += lambdaIneq[i] * row[j]
+		}
+	}
+	return g
+}
+
+func sqpViolation(pt sqpPoint) float64 {
+	v := 0.0
+	for _, c := range pt.cEq {
+		v += absO(c)
+	}
+	for _, c := range pt.cIneq {
+		if c > 0 {
+			v += c
+		}
+	}
+	return v
+}
+
+func sqpKKT(pt sqpPoint, lambdaEq, lambdaIneq []float64) float64 {
+	worst := vectorNormInf(sqpLagrangianGrad(pt, lambdaEq, lambdaIneq))
+	for _, c := range pt.cEq {
+		worst = math.Max(worst, absO(c))
+	}
+	for i, c := range pt.cIneq {
+		worst = math.Max(worst, math.Max(c, 0))
+		worst = math.Max(worst, math.Max(-lambdaIneq[i], 0))
+		worst = math.Max(worst, absO(lambdaIneq[i]*c))
+	}
+	return worst
+}
+
+func SQP(problem SQPProblem, x0 []float64, settings SQPSettings) SQPResult {
+	n := len(x0)
+	x := cloneVector(x0)
+	B := identityMat(n)
+	pt := sqpEvaluate(problem, x, settings.DiffStep)
+	lambdaEq := make([]float64, len(pt.cEq))
+	lambdaIneq := make([]float64, len(pt.cIneq))
+	penalty := 1.0
+	result := SQPResult{}
+	for iter := 0; iter < settings.MaxIter; iter++ {
+		result.Iterations = iter + 1
+		d, yEq, zIneq := solveQPInteriorPoint(B, pt.grad, pt.aEq, pt.cEq, pt.aIneq, pt.cIneq, settings.QPIterations)
+		if sqpKKT(pt, yEq, zIneq) < settings.Tol {
+			lambdaEq, lambdaIneq = yEq, zIneq
+			result.Converged = true
+			break
+		}
+		for _, v := range yEq {
+			penalty = math.Max(penalty, 1.1*absO(v))
+		}
+		for _, v := range zIneq {
+			penalty = math.Max(penalty, 1.1*absO(v))
+		}
+		merit := pt.f + penalty*sqpViolation(pt)
+		slope := dotProd(pt.grad, d) - penalty*sqpViolation(pt)
+		if slope > 0 {
+			slope = -dotProd(d, matVec(B, d))
+		}
+		alpha := 1.0
+		var next sqpPoint
+		var xNext []float64
+		for {
+			xNext = addScaled(x, d, alpha)
+			next = sqpEvaluate(problem, xNext, settings.DiffStep)
+			if next.f+penalty*sqpViolation(next) <= merit+settings.LineC1*alpha*slope || alpha < settings.MinStep {
+				break
+			}
+			alpha *= settings.LineTau
+		}
+		if alpha < settings.MinStep {
+			B = identityMat(n)
+			lambdaEq, lambdaIneq = yEq, zIneq
+			if vecNorm(d) < settings.Tol {
+				break
+			}
+			continue
+		}
+		s := subVec(xNext, x)
+		yl := subVec(sqpLagrangianGrad(next, yEq, zIneq), sqpLagrangianGrad(pt, yEq, zIneq))
+		B = dampedBFGSUpdate(B, s, yl)
+		x, pt = xNext, next
+		lambdaEq, lambdaIneq = yEq, zIneq
+		if vecNorm(s) < settings.Tol*(1+vecNorm(x)) && sqpKKT(pt, lambdaEq, lambdaIneq) < math.Sqrt(settings.Tol) {
+			result.Converged = true
+			break
+		}
+	}
+	result.X = x
+	result.Value = pt.f
+	result.KKTViolation = sqpKKT(pt, lambdaEq, lambdaIneq)
+	result.LambdaEq = lambdaEq
+	m := len(problem.Ineq)
+	result.LambdaIneq = cloneVector(lambdaIneq[:m])
+	if len(problem.Bounds) == n {
+		result.LambdaLower = make([]float64, n)
+		result.LambdaUpper = make([]float64, n)
+		k := m
+		for j := 0; j < n; j++ {
+			if !math.IsInf(problem.Bounds[j][0], -1) {
+				result.LambdaLower[j] = lambdaIneq[k]
+				k++
+			}
+			if !math.IsInf(problem.Bounds[j][1], 1) {
+				result.LambdaUpper[j] = lambdaIneq[k]
+				k++
+			}
+		}
+	}
+	return result
+}
+
+func dampedBFGSUpdate(B [][]float64, s, y []float64) [][]float64 {
+	Bs := matVec(B, s)
+	sBs := dotProd(s, Bs)
+	sy := dotProd(s, y)
+	if sBs <= 1e-16 {
+		return B
+	}
+	r := y
+	if sy < 0.2*sBs {
+		theta := 0.8 * sBs / (sBs - sy)
+		r = make([]float64, len(y))
+		for i := range y {
+			r[i] = theta*y[i] + (1-theta)*Bs[i]
+		}
+	}
+	sr := dotProd(s, r)
+	out := make([][]float64, len(B))
+	for i := range B {
+		out[i] = make([]float64, len(B))
+		for j := range B {
+			out[i][j] = B[i][j] + r[i]*r[j]/sr - Bs[i]*Bs[j]/sBs
+		}
+	}
+	return out
+}
+
+func solveQPInteriorPoint(B [][]float64, g []float64, aEq [][]float64, cEq []float64, aIneq [][]float64, cIneq []float64, maxIter int) ([]float64, []float64, []float64) {
+	n := len(g)
+	me := len(cEq)
+	mi := len(cIneq)
+	d := make([]float64, n)
+	y := make([]float64, me)
+	z := make([]float64, mi)
+	s := make([]float64, mi)
+	for i := range s {
+		s[i] = math.Max(1, -cIneq[i])
+		z[i] = 1
+	}
+	for iter := 0; iter < maxIter; iter++ {
+		rd := matVec(B, d)
+		for j := range rd {
+			rd[j] += g[j]
+		}
+		for i, row := range aEq {
+			for j := range rd {
+				rd[j] += y[i] * row[j]
+			}
+		}
+		for i, row := range aIneq {
+			for j := range rd {
+				rd[j] += z[i] * row[j]
+			}
+		}
+		rp := make([]float64, me)
+		for i, row := range aEq {
+			rp[i] = dotProd(row, d) + cEq[i]
+		}
+		ri := make([]float64, mi)
+		mu := 0.0
+		for i, row := range aIneq {
+			ri[i] = dotProd(row, d) + s[i] + cIneq[i]
+			mu += s[i] * z[i]
+		}
+		if mi > 0 {
+			mu /= float64(mi)
+		}
+		if vectorNormInf(rd) < 1e-12 && vectorNormInf(rp) < 1e-12 && vectorNormInf(ri) < 1e-12 && mu < 1e-12 {
+			break
+		}
+		sigma := 0.1
+		size := n + me
+		K := make([][]float64, size)
+		rhs := make([]float64, size)
+		for i := range K {
+			K[i] = make([]float64, size)
+		}
+		for i := 0; i < n; i++ {
+			copy(K[i][:n], B[i])
+			rhs[i] = -rd[i]
+		}
+		for k, row := range aIneq {
+			w := z[k] / s[k]
+			corr := (sigma*mu - s[k]*z[k] + z[k]*ri[k]) / s[k]
+			for i := 0; i < n; i++ {
+				rhs[i] -= row[i] * corr
+				for j := 0; j < n; j++ {
+					K[i][j] += w * row[i] * row[j]
+				}
+			}
+		}
+		for k, row := range aEq {
+			for j := 0; j < n; j++ {
+				K[n+k][j] = row[j]
+				K[j][n+k] = row[j]
+			}
+			K[n+k][n+k] = -1e-12
+			rhs[n+k] = -rp[k]
+		}
+		sol := solveDenseSystem(K, rhs)
+		dd := sol[:n]
+		dy := sol[n:]
+		ds := make([]float64, mi)
+		dz := make([]float64, mi)
+		for k, row := range aIneq {
+			ds[k] = -ri[k] - dotProd(row, dd)
+			dz[k] = (sigma*mu - s[k]*z[k] - z[k]*ds[k]) / s[k]
+		}
+		step := 1.0
+		for k := 0; k < mi; k++ {
+			if ds[k] < 0 {
+				step = math.Min(step, -0.995*s[k]/ds[k])
+			}
+			if dz[k] < 0 {
+				step = math.Min(step, -0.995*z[k]/dz[k])
+			}
+		}
+		for j := range d {
+			d[j] += step * dd[j]
+		}
+		for k := range y {
+			y[k] += step * dy[k]
+		}
+		for k := 0; k < mi; k++ {
+			s[k] += step * ds[k]
+			z[k] += step * dz[k]
+		}
+	}
+	return d, y, z
+}
+
+func solveDenseSystem(A [][]float64, b []float64) []float64 {
+	n := len(b)
+	aug := make([][]float64, n)
+	for i := range aug {
+		aug[i] = make([]float64, n+1)
+		copy(aug[i], A[i])
+		aug[i][n] = b[i]
+	}
+	for col := 0; col < n; col++ {
+		pivotRow := col
+		for r := col + 1; r < n; r++ {
+			if absO(aug[r][col]) > absO(aug[pivotRow][col]) {
+				pivotRow = r
+			}
+		}
+		aug[col], aug[pivotRow] = aug[pivotRow], aug[col]
+		if absO(aug[col][col]) < 1e-300 {
+			continue
+		}
+		for r := col + 1; r < n; r++ {
+			factor := aug[r][col] / aug[col][col]
+			for c := col; c <= n; c++ {
+				aug[r][c] -= factor * aug[col][c]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := aug[i][n]
+		for j := i + 1; j < n; j++ {
+			sum -= aug[i][j] * x[j]
+		}
+		if absO(aug[i][i]) < 1e-300 {
+			x[i] = 0
+			continue
+		}
+		x[i] = sum / aug[i][i]
+	}
+	return x
+}
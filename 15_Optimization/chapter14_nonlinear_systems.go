@@ -0,0 +1,250 @@
+// 2026 Update: Nonlinear Systems
+package optimization
+
+import "math"
+
+type VectorFunc func(x []float64) []float64
+
+type JacobianFunc func(x []float64) [][]float64
+
+type SystemRootResult struct {
+	Root                []float64
+	Iterations          int
+	Evaluations         int
+	JacobianEvaluations int
+	Converged           bool
+	Residual            float64
+}
+
+type BroydenUpdate int
+
+const (
+	BroydenGood BroydenUpdate = iota
+	BroydenBad
+)
+
+func FiniteDifferenceJacobian(F VectorFunc, x, fx []float64, h float64) [][]float64 {
+	n := len(x)
+	J := make([][]float64, len(fx))
+	for i := range J {
+		J[i] = make([]float64, n)
+	}
+	xp := cloneVector(x)
+	for j := 0; j < n; j++ {
+		step := h * (1 + absO(x[j]))
+		xp[j] = x[j] + step
+		fp := F(xp)
+		for i := range fx {
+			J[i][j] = (fp[i] - fx[i]) / step
+		}
+		xp[j] = x[j]
+	}
+	return J
+}
+
+type systemState struct {
+	F      VectorFunc
+	J      JacobianFunc
+	h      float64
+	evals  int
+	jevals int
+}
+
+func (s *systemState) eval(x []float64) []float64 {
+	s.evals++
+	return s.F(x)
+}
+
+func (s *systemState) jacobian(x, fx []float64) [][]float64 {
+	s.jevals++
+	if s.J != nil {
+		return s.J(x)
+	}
+	s.evals += len(x)
+	return FiniteDifferenceJacobian(s.F, x, fx, s.h)
+}
+
+func (s *systemState) result(x []float64, fx []float64, iters int, converged bool) SystemRootResult {
+	return SystemRootResult{
+		Root:                x,
+		Iterations:          iters,
+		Evaluations:         s.evals,
+		JacobianEvaluations: s.jevals,
+		Converged:           converged,
+		Residual:            vecNorm(fx),
+	}
+}
+
+func systemConverged(x, prev, fx []float64, settings RootSettings) bool {
+	if vectorNormInf(fx) <= settings.AbsTol {
+		return true
+	}
+	if prev == nil {
+		return false
+	}
+	return vectorNormInf(subVec(x, prev)) <= settings.AbsTol+settings.RelTol*vectorNormInf(x)
+}
+
+func NewtonSystem(F VectorFunc, J JacobianFunc, x0 []float64, settings RootSettings) SystemRootResult {
+	st := &systemState{F: F, J: J, h: settings.DerivStep}
+	x := cloneVector(x0)
+	fx := st.eval(x)
+	if systemConverged(x, nil, fx, settings) {
+		return st.result(x, fx, 0, true)
+	}
+	for iter := 1; iter <= settings.MaxIter; iter++ {
+		jac := st.jacobian(x, fx)
+		dx := solveDenseSystem(jac, scaleVec(fx, -1))
+		prev := x
+		x, fx = systemLineSearch(st, x, fx, dx)
+		if systemConverged(x, prev, fx, settings) && vectorNormInf(fx) <= math.Sqrt(settings.AbsTol) {
+			return st.result(x, fx, iter, true)
+		}
+	}
+	return st.result(x, fx, settings.MaxIter, false)
+}
+
+func systemLineSearch(st *systemState, x, fx, dx []float64) ([]float64, []float64) {
+	phi0 := 0.5 * dotProd(fx, fx)
+	alpha := 1.0
+	for k := 0; k < 30; k++ {
+		xn := addScaled(x, dx, alpha)
+		fn := st.eval(xn)
+		phi := 0.5 * dotProd(fn, fn)
+		if phi <= (1-2e-4*alpha)*phi0 || k == 29 {
+			return xn, fn
+		}
+		alpha *= 0.5
+	}
+	return x, fx
+}
+
+func BroydenSystem(F VectorFunc, J JacobianFunc, x0 []float64, update BroydenUpdate, settings RootSettings) SystemRootResult {
+	st := &systemState{F: F, J: J, h: settings.DerivStep}
+	x := cloneVector(x0)
+	fx := st.eval(x)
+	if systemConverged(x, nil, fx, settings) {
+		return st.result(x, fx, 0, true)
+	}
+	H := invertDense(st.jacobian(x, fx))
+	for iter := 1; iter <= settings.MaxIter; iter++ {
+		dx := scaleVec(matVec(H, fx), -1)
+		xn, fn := systemLineSearch(st, x, fx, dx)
+		if dotProd(fn, fn) >= dotProd(fx, fx) {
+			H = invertDense(st.jacobian(x, fx))
+			dx = scaleVec(matVec(H, fx), -1)
+			xn, fn = systemLineSearch(st, x, fx, dx)
+		}
+		s := subVec(xn, x)
+		y := subVec(fn, fx)
+		Hy := matVec(H, y)
+		diff := subVec(s, Hy)
+		switch update {
+		case BroydenBad:
+			yy := dotProd(y, y)
+			if yy > 1e-300 {
+				for i := range H {
+					for j := range H[i] {
+						H[i][j] += diff[i] * y[j] / yy
+					}
+				}
+			}
+		default:
+			sH := matVec(transposeMat(H), s)
+			denom := dotProd(s, Hy)
+			if absO(denom) > 1e-300 {
+				for i := range H {
+					for j := range H[i] {
+						H[i][j] += diff[i] * sH[j] / denom
+					}
+				}
+			}
+		}
+		prev := x
+		x, fx = xn, fn
+		if systemConverged(x, prev, fx, settings) && vectorNormInf(fx) <= math.Sqrt(settings.AbsTol) {
+			return st.result(x, fx, iter, true)
+		}
+	}
+	return st.result(x, fx, settings.MaxIter, false)
+}
+
+func invertDense(A [][]float64) [][]float64 {
+	n := len(A)
+	inv := make([][]float64, n)
+	for i := range inv {
+		inv[i] = make([]float64, n)
+	}
+	for j := 0; j < n; j++ {
+		e := make([]float64, n)
+		e[j] = 1
+		col := solveDenseSystem(A, e)
+		for i := 0; i < n; i++ {
+			inv[i][j] = col[i]
+		}
+	}
+	return inv
+}
+
+func DoglegSystem(F VectorFunc, J JacobianFunc, x0 []float64, settings RootSettings) SystemRootResult {
+	st := &systemState{F: F, J: J, h: settings.DerivStep}
+	x := cloneVector(x0)
+	fx := st.eval(x)
+	if systemConverged(x, nil, fx, settings) {
+		return st.result(x, fx, 0, true)
+	}
+	radius := math.Max(1, vecNorm(x))
+	jac := st.jacobian(x, fx)
+	for iter := 1; iter <= settings.MaxIter; iter++ {
+		g := matVec(transposeMat(jac), fx)
+		Jg := matVec(jac, g)
+		gg := dotProd(g, g)
+		JgJg := dotProd(Jg, Jg)
+		cauchy := make([]float64, len(x))
+		if JgJg > 0 {
+			cauchy = scaleVec(g, -gg/JgJg)
+		}
+		newton := solveDenseSystem(jac, scaleVec(fx, -1))
+		var step []float64
+		switch {
+		case vecNorm(newton) <= radius:
+			step = newton
+		case vecNorm(cauchy) >= radius:
+			step = scaleVec(g, -radius/math.Sqrt(gg))
+		default:
+			diff := subVec(newton, cauchy)
+			a := dotProd(diff, diff)
+			b := 2 * dotProd(cauchy, diff)
+			c := dotProd(cauchy, cauchy) - radius*radius
+			tau := (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+			step = addScaled(cauchy, diff, tau)
+		}
+		predicted := addVec(fx, matVec(jac, step))
+		predReduction := dotProd(fx, fx) - dotProd(predicted, predicted)
+		xn := addVec(x, step)
+		fn := st.eval(xn)
+		actReduction := dotProd(fx, fx) - dotProd(fn, fn)
+		rho := 0.0
+		if predReduction > 0 {
+			rho = actReduction / predReduction
+		}
+		stepNorm := vecNorm(step)
+		if rho < 0.25 {
+			radius = 0.25 * stepNorm
+		} else if rho > 0.75 && stepNorm >= 0.99*radius {
+			radius = math.Min(2*radius, 1e10)
+		}
+		if rho > 1e-4 {
+			prev := x
+			x, fx = xn, fn
+			if systemConverged(x, prev, fx, settings) && vectorNormInf(fx) <= math.Sqrt(settings.AbsTol) {
+				return st.result(x, fx, iter, true)
+			}
+			jac = st.jacobian(x, fx)
+		}
+		if radius < settings.AbsTol*(1+vecNorm(x)) {
+			return st.result(x, fx, iter, vectorNormInf(fx) <= settings.AbsTol)
+		}
+	}
+	return st.result(x, fx, settings.MaxIter, false)
+}
@@ -0,0 +1,415 @@
+// 2026 Update: Benchmark Functions
+package optimization
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+type BenchmarkFunction struct {
+	Name         string
+	Dim          int
+	F            ObjectiveFunc
+	Grad         func([]float64) []float64
+	Bounds       [][2]float64
+	Optimum      []float64
+	OptimumValue float64
+}
+
+func uniformBounds(dim int, low, high float64) [][2]float64 {
+	b := make([][2]float64, dim)
+	for i := range b {
+		b[i] = [2]float64{low, high}
+	}
+	return b
+}
+
+func filledVector(dim int, v float64) []float64 {
+	out := make([]float64, dim)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func SphereBenchmark(dim int) BenchmarkFunction {
+	return BenchmarkFunction{
+		Name: "Sphere",
+		Dim:  dim,
+		F: func(x []float64) float64 {
+			return dotProd(x, x)
+		},
+		Grad: func(x []float64) []float64 {
+			return scaleVec(x, 2)
+		},
+		Bounds:       uniformBounds(dim, -5.12, 5.12),
+		Optimum:      make([]float64, dim),
+		OptimumValue: 0,
+	}
+}
+
+func RosenbrockBenchmark(dim int) BenchmarkFunction {
+	return BenchmarkFunction{
+		Name: "Rosenbrock",
+		Dim:  dim,
+		F: func(x []float64) float64 {
+			s := 0.0
+			for i := 0; i+1 < len(x); i++ {
+				a := x[i+1] - x[i]*x[i]
+				b := 1 - x[i]
+				s += 100*a*a + b*b
+			}
+			return s
+		},
+		Grad: func(x []float64) []float64 {
+			g := make([]float64, len(x))
+			for i := 0; i+1 < len(x); i++ {
+				a := x[i+1] - x[i]*x[i]
+				g[i] += -400*x[i]*a - 2*(1-x[i])
+				g[i+1] += 200 * a
+			}
+			return g
+		},
+		Bounds:       uniformBounds(dim, -5, 10),
+		Optimum:      filledVector(dim, 1),
+		OptimumValue: 0,
+	}
+}
+
+func RastriginBenchmark(dim int) BenchmarkFunction {
+	return BenchmarkFunction{
+		Name: "Rastrigin",
+		Dim:  dim,
+		F: func(x []float64) float64 {
+			s := 10 * float64(len(x))
+			for _, v := range x {
+				s += v*v - 10*math.Cos(2*math.Pi*v)
+			}
+			return s
+		},
+		Grad: func(x []float64) []float64 {
+			g := make([]float64, len(x))
+			for i, v := range x {
+				g[i] = 2*v + 20*math.Pi*math.Sin(2*math.Pi*v)
+			}
+			return g
+		},
+		Bounds:       uniformBounds(dim, -5.12, 5.12),
+		Optimum:      make([]float64, dim),
+		OptimumValue: 0,
+	}
+}
+
+func AckleyBenchmark(dim int) BenchmarkFunction {
+	return BenchmarkFunction{
+		Name: "Ackley",
+		Dim:  dim,
+		F: func(x []float64) float64 {
+			n := float64(len(x))
+			sq, cs := 0.0, 0.0
+			for _, v := range x {
+				sq += v * v
+				cs += math.Cos(2 * math.Pi * v)
+			}
+			return -20*math.Exp(-0.2*math.Sqrt(sq/n)) - math.Exp(cs/n) + 20 + math.E
+		},
+		Grad: func(x []float64) []float64 {
+			n := float64(len(x))
+			sq, cs := 0.0, 0.0
+			for _, v := range x {
+				sq += v * v
+				cs += math.Cos(2 * math.Pi * v)
+			}
+			r := math.Sqrt(sq / n)
+			g := make([]float64, len(x))
+			for i, v := range x {
+				if r > 0 {
+					g[i] = 4 * math.Exp(-0.2*r) * v / (n * r)
+				}
+				g[i] += math.Exp(cs/n) * 2 * math.Pi * math.Sin(2*math.Pi*v) / n
+			}
+			return g
+		},
+		Bounds:       uniformBounds(dim, -32.768, 32.768),
+		Optimum:      make([]float64, dim),
+		OptimumValue: 0,
+	}
+}
+
+func GriewankBenchmark(dim int) BenchmarkFunction {
+	return BenchmarkFunction{
+		Name: "Griewank",
+		Dim:  dim,
+		F: func(x []float64) float64 {
+			s, p := 0.0, 1.0
+			for i, v := range x {
+				s += v * v / 4000
+				p *= math.Cos(v / math.Sqrt(float64(i+1)))
+			}
+			return 1 + s - p
+		},
+		Grad: func(x []float64) []float64 {
+			g := make([]float64, len(x))
+			for i, v := range x {
+				root := math.Sqrt(float64(i + 1))
+				p := math.Sin(v/root) / root
+				for j, w := range x {
+					if j != i {
+						p *= math.Cos(w / math.Sqrt(float64(j+1)))
+					}
+				}
+				g[i] = v/2000 + p
+			}
+			return g
+		},
+		Bounds:       uniformBounds(dim, -600, 600),
+		Optimum:      make([]float64, dim),
+		OptimumValue: 0,
+	}
+}
+
+func StyblinskiTangBenchmark(dim int) BenchmarkFunction {
+	const root = -2.903534027771178
+	return BenchmarkFunction{
+		Name: "StyblinskiTang",
+		Dim:  dim,
+		F: func(x []float64) float64 {
+			s := 0.0
+			for _, v := range x {
+				s += v*v*v*v - 16*v*v + 5*v
+			}
+			return s / 2
+		},
+		Grad: func(x []float64) []float64 {
+			g := make([]float64, len(x))
+			for i, v := range x {
+				g[i] = 2*v*v*v - 16*v + 2.5
+			}
+			return g
+		},
+		Bounds:       uniformBounds(dim, -5, 5),
+		Optimum:      filledVector(dim, root),
+		OptimumValue: float64(dim) * (root*root*root*root - 16*root*root + 5*root) / 2,
+	}
+}
+
+func HimmelblauBenchmark() BenchmarkFunction {
+	return BenchmarkFunction{
+		Name: "Himmelblau",
+		Dim:  2,
+		F: func(x []float64) float64 {
+			a := x[0]*x[0] + x[1] - 11
+			b := x[0] + x[1]*x[1] - 7
+			return a*a + b*b
+		},
+		Grad: func(x []float64) []float64 {
+			a := x[0]*x[0] + x[1] - 11
+			b := x[0] + x[1]*x[1] - 7
+			return []float64{4*x[0]*a + 2*b, 2*a + 4*x[1]*b}
+		},
+		Bounds:       uniformBounds(2, -5, 5),
+		Optimum:      []float64{3, 2},
+		OptimumValue: 0,
+	}
+}
+
+func RandomRotation(dim int, seed uint64) [][]float64 {
+	rng := NewRNG(seed)
+	q := make([][]float64, dim)
+	for i := 0; i < dim; i++ {
+		v := make([]float64, dim)
+		for {
+			for j := range v {
+				v[j] = normalSample(rng)
+			}
+			for k := 0; k < i; k++ {
+				v = addScaled(v, q[k], -dotProd(v, q[k]))
+			}
+			if norm := vecNorm(v); norm > 1e-8 {
+				v = scaleVec(v, 1/norm)
+				break
+			}
+		}
+		q[i] = v
+	}
+	return q
+}
+
+func ShiftedRotatedBenchmark(base BenchmarkFunction, seed uint64) BenchmarkFunction {
+	dim := base.Dim
+	M := RandomRotation(dim, seed)
+	Mt := transposeMat(M)
+	rng := NewRNG(seed ^ 0x5DEECE66D)
+	shift := make([]float64, dim)
+	for i := range shift {
+		low, high := base.Bounds[i][0], base.Bounds[i][1]
+		mid := (low + high) / 2
+		shift[i] = mid + 0.8*(rng.Float64()-0.5)*(high-low)
+	}
+	transform := func(x []float64) []float64 {
+		return addVec(base.Optimum, matVec(M, subVec(x, shift)))
+	}
+	out := BenchmarkFunction{
+		Name: "ShiftedRotated" + base.Name,
+		Dim:  dim,
+		F: func(x []float64) float64 {
+			return base.F(transform(x))
+		},
+		Bounds:       base.Bounds,
+		Optimum:      shift,
+		OptimumValue: base.OptimumValue,
+	}
+	if base.Grad != nil {
+		out.Grad = func(x []float64) []float64 {
+			return matVec(Mt, base.Grad(transform(x)))
+		}
+	}
+	return out
+}
+
+func StandardBenchmarks(dim int) []BenchmarkFunction {
+	return []BenchmarkFunction{
+		SphereBenchmark(dim),
+		RosenbrockBenchmark(dim),
+		RastriginBenchmark(dim),
+		AckleyBenchmark(dim),
+		GriewankBenchmark(dim),
+		StyblinskiTangBenchmark(dim),
+		ShiftedRotatedBenchmark(RosenbrockBenchmark(dim), 7),
+		ShiftedRotatedBenchmark(RastriginBenchmark(dim), 11),
+	}
+}
+
+type BenchmarkSolver struct {
+	Name  string
+	Solve func(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, bounds [][2]float64, seed uint64) []float64
+}
+
+func DefaultBenchmarkSolvers() []BenchmarkSolver {
+	return []BenchmarkSolver{
+		{Name: "NelderMead", Solve: func(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, bounds [][2]float64, seed uint64) []float64 {
+			return NelderMeadWithSettings(f, x0, DefaultNelderMeadSettings())
+		}},
+		{Name: "BFGS", Solve: func(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, bounds [][2]float64, seed uint64) []float64 {
+			return BFGSWithSettings(f, grad, x0, DefaultBFGSSettings())
+		}},
+		{Name: "PSO", Solve: func(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, bounds [][2]float64, seed uint64) []float64 {
+			settings := DefaultPSOSettings()
+			settings.InitialSeed = seed
+			return PSOWithSettings(f, len(x0), 30, bounds, settings)
+		}},
+		{Name: "DE", Solve: func(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, bounds [][2]float64, seed uint64) []float64 {
+			settings := DefaultDESettings(10*len(x0), 300)
+			settings.Seed = seed
+			return DifferentialEvolutionWithSettings(f, len(x0), bounds, settings)
+		}},
+		{Name: "CMAES", Solve: func(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, bounds [][2]float64, seed uint64) []float64 {
+			settings := DefaultCMAESFullSettings(bounds)
+			settings.Seed = seed
+			settings.Sigma = 0.3 * (bounds[0][1] - bounds[0][0])
+			settings.Restart = CMAESIPOP
+			return CMAESFull(f, x0, settings)
+		}},
+	}
+}
+
+type BenchmarkSettings struct {
+	Seeds  []uint64
+	Target float64
+}
+
+func DefaultBenchmarkSettings() BenchmarkSettings {
+	return BenchmarkSettings{
+		Seeds:  []uint64{1, 2, 3, 4, 5},
+		Target: 1e-6,
+	}
+}
+
+type BenchmarkRecord struct {
+	Solver            string
+	Function          string
+	Dim               int
+	Runs              int
+	Successes         int
+	SuccessRate       float64
+	MeanEvaluations   float64
+	MeanEvalsToTarget float64
+	MeanFinalError    float64
+	BestFinalError    float64
+}
+
+func RunBenchmarks(solvers []BenchmarkSolver, functions []BenchmarkFunction, settings BenchmarkSettings) []BenchmarkRecord {
+	records := []BenchmarkRecord{}
+	for _, fn := range functions {
+		for _, solver := range solvers {
+			rec := BenchmarkRecord{Solver: solver.Name, Function: fn.Name, Dim: fn.Dim, BestFinalError: math.Inf(1)}
+			hitEvals := 0.0
+			for _, seed := range settings.Seeds {
+				var evals, hit int64
+				threshold := fn.OptimumValue + settings.Target
+				counted := func(x []float64) float64 {
+					n := atomic.AddInt64(&evals, 1)
+					v := fn.F(x)
+					if v <= threshold {
+						atomic.CompareAndSwapInt64(&hit, 0, n)
+					}
+					return v
+				}
+				grad := fn.Grad
+				if grad == nil {
+					grad = func(x []float64) []float64 {
+						return finiteDiffGradQ(fn.F, x, 1e-7)
+					}
+				}
+				x0 := randomVector(NewRNG(seed), fn.Dim, fn.Bounds)
+				x := solver.Solve(counted, grad, x0, fn.Bounds, seed)
+				errVal := math.Max(fn.F(x)-fn.OptimumValue, 0)
+				if math.IsNaN(errVal) {
+					errVal = math.Inf(1)
+				}
+				rec.Runs++
+				rec.MeanEvaluations += float64(evals)
+				rec.MeanFinalError += errVal
+				rec.BestFinalError = math.Min(rec.BestFinalError, errVal)
+				if errVal <= settings.Target {
+					rec.Successes++
+					if hit == 0 {
+						hit = evals
+					}
+					hitEvals += float64(hit)
+				}
+			}
+			if rec.Runs > 0 {
+				rec.SuccessRate = float64(rec.Successes) / float64(rec.Runs)
+				rec.MeanEvaluations /= float64(rec.Runs)
+				rec.MeanFinalError /= float64(rec.Runs)
+			}
+			rec.MeanEvalsToTarget = math.NaN()
+			if rec.Successes > 0 {
+				rec.MeanEvalsToTarget = hitEvals / float64(rec.Successes)
+			}
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+func BenchmarkTable(records []BenchmarkRecord) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-28s %-12s %4s %8s %12s %12s %12s\n", "function", "solver", "dim", "success", "evals", "evals@target", "mean error")
+	for _, r := range records {
+		fmt.Fprintf(&sb, "%-28s %-12s %4d %7.0f%% %12.0f %12.0f %12.3e\n", r.Function, r.Solver, r.Dim, 100*r.SuccessRate, r.MeanEvaluations, r.MeanEvalsToTarget, r.MeanFinalError)
+	}
+	return sb.String()
+}
+
+func BenchmarkCSV(records []BenchmarkRecord) string {
+	var sb strings.Builder
+	sb.WriteString("function,solver,dim,runs,successes,success_rate,mean_evaluations,mean_evals_to_target,mean_final_error,best_final_error\n")
+	for _, r := range records {
+		fmt.Fprintf(&sb, "%s,%s,%d,%d,%d,%g,%g,%g,%g,%g\n", r.Function, r.Solver, r.Dim, r.Runs, r.Successes, r.SuccessRate, r.MeanEvaluations, r.MeanEvalsToTarget, r.MeanFinalError, r.BestFinalError)
+	}
+	return sb.String()
+}
@@ -0,0 +1,559 @@
+// 2026 Update: Proximal Methods
+package optimization
+
+import (
+	"math"
+	"sort"
+)
+
+// ProxOperator is a possibly non-smooth, closed convex term g. Prox returns
+// argmin_x g(x) + ||x - v||² / (2t); Value returns +Inf outside the domain.
+type ProxOperator interface {
+	Prox(v []float64, t float64) []float64
+	Value(x []float64) float64
+}
+
+type ZeroProx struct{}
+
+func (ZeroProx) Prox(v []float64, t float64) []float64 {
+	return cloneVector(v)
+}
+
+func (ZeroProx) Value(x []float64) float64 {
+	return 0
+}
+
+type L1Prox struct {
+	Lambda float64
+}
+
+func (p L1Prox) Prox(v []float64, t float64) []float64 {
+	out := make([]float64, len(v))
+	for i, vi := range v {
+		out[i] = softThreshold(vi, p.Lambda*t)
+	}
+	return out
+}
+
+func (p L1Prox) Value(x []float64) float64 {
+	s := 0.0
+	for _, v := range x {
+		s += absO(v)
+	}
+	return p.Lambda * s
+}
+
+func softThreshold(v, k float64) float64 {
+	switch {
+	case v > k:
+		return v - k
+	case v < -k:
+		return v + k
+	default:
+		return 0
+	}
+}
+
+type GroupLassoProx struct {
+	Lambda float64
+	Groups [][]int
+}
+
+func (p GroupLassoProx) Prox(v []float64, t float64) []float64 {
+	out := cloneVector(v)
+	for _, group := range p.Groups {
+		norm := 0.0
+		for _, i := range group {
+			norm += v[i] * v[i]
+		}
+		norm = math.Sqrt(norm)
+		scale := 0.0
+		if norm > p.Lambda*t {
+			scale = 1 - p.Lambda*t/norm
+		}
+		for _, i := range group {
+			out[i] = scale * v[i]
+		}
+	}
+	return out
+}
+
+func (p GroupLassoProx) Value(x []float64) float64 {
+	s := 0.0
+	for _, group := range p.Groups {
+		norm := 0.0
+		for _, i := range group {
+			norm += x[i] * x[i]
+		}
+		s += math.Sqrt(norm)
+	}
+	return p.Lambda * s
+}
+
+type BoxProx struct {
+	Bounds [][2]float64
+}
+
+func (p BoxProx) Prox(v []float64, t float64) []float64 {
+	return clampVector(v, p.Bounds)
+}
+
+func (p BoxProx) Value(x []float64) float64 {
+	for i, v := range x {
+		if v < p.Bounds[i][0] || v > p.Bounds[i][1] {
+			return math.Inf(1)
+		}
+	}
+	return 0
+}
+
+// SimplexProx is the indicator of {x >= 0, Σx = 1}. Its prox is the
+// Euclidean projection, not the renormalization done by SimplexProjection.
+type SimplexProx struct{}
+
+func (SimplexProx) Prox(v []float64, t float64) []float64 {
+	return EuclideanSimplexProjection(v)
+}
+
+func (SimplexProx) Value(x []float64) float64 {
+	sum := 0.0
+	for _, v := range x {
+		if v < -1e-12 {
+			return math.Inf(1)
+		}
+		sum += v
+	}
+	if absO(sum-1) > 1e-9 {
+		return math.Inf(1)
+	}
+	return 0
+}
+
+func EuclideanSimplexProjection(v []float64) []float64 {
+	if len(v) == 0 {
+		return nil
+	}
+	sorted := cloneVector(v)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	cum := 0.0
+	theta := 0.0
+	for i, u := range sorted {
+		cum += u
+		if t := (cum - 1) / float64(i+1); u-t > 0 {
+			theta = t
+		}
+	}
+	out := make([]float64, len(v))
+	for i, vi := range v {
+		out[i] = math.Max(vi-theta, 0)
+	}
+	return out
+}
+
+// NuclearNormProx acts on a Rows×Cols matrix stored row-major in the vector.
+type NuclearNormProx struct {
+	Lambda float64
+	Rows   int
+	Cols   int
+}
+
+func (p NuclearNormProx) svd(x []float64) ([][]float64, []float64, [][]float64) {
+	X := make([][]float64, p.Rows)
+	for i := range X {
+		X[i] = x[i*p.Cols : (i+1)*p.Cols]
+	}
+	gram := make([][]float64, p.Cols)
+	for a := range gram {
+		gram[a] = make([]float64, p.Cols)
+		for b := 0; b <= a; b++ {
+			s := 0.0
+			for i := range X {
+				s += X[i][a] * X[i][b]
+			}
+			gram[a][b] = s
+			gram[b][a] = s
+		}
+	}
+	eig, V := jacobiEigen(gram, 100)
+	sigma := make([]float64, len(eig))
+	for j, l := range eig {
+		sigma[j] = math.Sqrt(math.Max(l, 0))
+	}
+	return X, sigma, V
+}
+
+func (p NuclearNormProx) Prox(v []float64, t float64) []float64 {
+	X, sigma, V := p.svd(v)
+	weights := make([]float64, len(sigma))
+	for j, s := range sigma {
+		if s > 0 {
+			weights[j] = math.Max(s-p.Lambda*t, 0) / s
+		}
+	}
+	out := make([]float64, len(v))
+	for i := range X {
+		proj := make([]float64, p.Cols)
+		for j := range proj {
+			for k := 0; k < p.Cols; k++ {
+				proj[j] += X[i][k] * V[k][j]
+			}
+			proj[j] *= weights[j]
+		}
+		for k := 0; k < p.Cols; k++ {
+			s := 0.0
+			for j := range proj {
+				s += proj[j] * V[k][j]
+			}
+			out[i*p.Cols+k] = s
+		}
+	}
+	return out
+}
+
+func (p NuclearNormProx) Value(x []float64) float64 {
+	_, sigma, _ := p.svd(x)
+	s := 0.0
+	for _, v := range sigma {
+		s += v
+	}
+	return p.Lambda * s
+}
+
+type QuadraticProx struct {
+	Q [][]float64
+	B []float64
+}
+
+func LeastSquaresProx(A [][]float64, b []float64) QuadraticProx {
+	n := len(A[0])
+	Q := make([][]float64, n)
+	for i := range Q {
+		Q[i] = make([]float64, n)
+		for j := range Q[i] {
+			for k := range A {
+				Q[i][j] += A[k][i] * A[k][j]
+			}
+		}
+	}
+	return QuadraticProx{Q: Q, B: rectMatTVec(A, b)}
+}
+
+func (p QuadraticProx) Prox(v []float64, t float64) []float64 {
+	n := len(v)
+	M := make([][]float64, n)
+	rhs := make([]float64, n)
+	for i := range M {
+		M[i] = cloneVector(p.Q[i])
+		M[i][i] += 1 / t
+		rhs[i] = p.B[i] + v[i]/t
+	}
+	return solveDenseSystem(M, rhs)
+}
+
+func (p QuadraticProx) Value(x []float64) float64 {
+	return 0.5*dotProd(x, matVec(p.Q, x)) - dotProd(p.B, x)
+}
+
+type SmoothProx struct {
+	F    ObjectiveFunc
+	Grad func([]float64) []float64
+}
+
+func (p SmoothProx) Prox(v []float64, t float64) []float64 {
+	obj := func(x []float64) float64 {
+		d := subVec(x, v)
+		return p.F(x) + dotProd(d, d)/(2*t)
+	}
+	var grad func([]float64) []float64
+	if p.Grad != nil {
+		grad = func(x []float64) []float64 {
+			return addScaled(p.Grad(x), subVec(x, v), 1/t)
+		}
+	} else {
+		grad = func(x []float64) []float64 {
+			return finiteDiffGradQ(obj, x, 1e-7)
+		}
+	}
+	return BFGSWithSettings(obj, grad, v, DefaultBFGSSettings())
+}
+
+func (p SmoothProx) Value(x []float64) float64 {
+	return p.F(x)
+}
+
+func rectMatVec(A [][]float64, x []float64) []float64 {
+	out := make([]float64, len(A))
+	for i, row := range A {
+		out[i] = dotProd(row, x)
+	}
+	return out
+}
+
+func rectMatTVec(A [][]float64, y []float64) []float64 {
+	out := make([]float64, len(A[0]))
+	for i, row := range A {
+		for j, a := range row {
+			out[j] += a * y[i]
+		}
+	}
+	return out
+}
+
+type ProximalSettings struct {
+	MaxIter   int
+	Tol       float64
+	Step      float64
+	Backtrack float64
+}
+
+func DefaultProximalSettings() ProximalSettings {
+	return ProximalSettings{
+		MaxIter:   5000,
+		Tol:       1e-10,
+		Step:      1.0,
+		Backtrack: 0.5,
+	}
+}
+
+type ProximalResult struct {
+	X          []float64
+	Value      float64
+	Iterations int
+	Converged  bool
+}
+
+func ISTA(f ObjectiveFunc, grad func([]float64) []float64, g ProxOperator, x0 []float64, settings ProximalSettings) ProximalResult {
+	return proximalGradient(f, grad, g, x0, settings, false)
+}
+
+func FISTA(f ObjectiveFunc, grad func([]float64) []float64, g ProxOperator, x0 []float64, settings ProximalSettings) ProximalResult {
+	return proximalGradient(f, grad, g, x0, settings, true)
+}
+
+func proximalGradient(f ObjectiveFunc, grad func([]float64) []float64, g ProxOperator, x0 []float64, settings ProximalSettings, accelerated bool) ProximalResult {
+	if grad == nil {
+		grad = func(x []float64) []float64 {
+			return finiteDiffGradQ(f, x, 1e-7)
+		}
+	}
+	if g == nil {
+		g = ZeroProx{}
+	}
+	step := settings.Step
+	x := cloneVector(x0)
+	y := cloneVector(x0)
+	momentum := 1.0
+	for iter := 1; iter <= settings.MaxIter; iter++ {
+		fy := f(y)
+		gy := grad(y)
+		var next []float64
+		for {
+			next = g.Prox(addScaled(y, gy, -step), step)
+			d := subVec(next, y)
+			if f(next) <= fy+dotProd(gy, d)+dotProd(d, d)/(2*step) || step < 1e-20 {
+				break
+			}
+			step *= settings.Backtrack
+		}
+		diff := subVec(next, x)
+		if accelerated {
+			nextMomentum := (1 + math.Sqrt(1+4*momentum*momentum)) / 2
+			if dotProd(subVec(y, next), diff) > 0 {
+				nextMomentum = 1
+				y = cloneVector(next)
+			} else {
+				y = addScaled(next, diff, (momentum-1)/nextMomentum)
+			}
+			momentum = nextMomentum
+		} else {
+			y = next
+		}
+		x = next
+		if vecNorm(diff) <= settings.Tol*(1+vecNorm(x)) {
+			return ProximalResult{X: x, Value: f(x) + g.Value(x), Iterations: iter, Converged: true}
+		}
+	}
+	return ProximalResult{X: x, Value: f(x) + g.Value(x), Iterations: settings.MaxIter, Converged: false}
+}
+
+type ADMMSettings struct {
+	Rho         float64
+	MaxIter     int
+	AbsTol      float64
+	RelTol      float64
+	AdaptiveRho bool
+}
+
+func DefaultADMMSettings() ADMMSettings {
+	return ADMMSettings{
+		Rho:         1.0,
+		MaxIter:     5000,
+		AbsTol:      1e-8,
+		RelTol:      1e-6,
+		AdaptiveRho: true,
+	}
+}
+
+type ADMMResult struct {
+	X              []float64
+	Z              []float64
+	U              []float64
+	Iterations     int
+	PrimalResidual float64
+	DualResidual   float64
+	Converged      bool
+}
+
+// ADMMProblem is min F(x) + G(z) subject to Ax = z. A nil A means the
+// identity; otherwise a QuadraticProx F is solved exactly and any other F
+// falls back to a linearized x-update.
+type ADMMProblem struct {
+	F ProxOperator
+	G ProxOperator
+	A [][]float64
+}
+
+func ADMM(problem ADMMProblem, x0 []float64, settings ADMMSettings) ADMMResult {
+	F, G := problem.F, problem.G
+	if F == nil {
+		F = ZeroProx{}
+	}
+	if G == nil {
+		G = ZeroProx{}
+	}
+	A := problem.A
+	apply := func(x []float64) []float64 {
+		if A == nil {
+			return cloneVector(x)
+		}
+		return rectMatVec(A, x)
+	}
+	applyT := func(y []float64) []float64 {
+		if A == nil {
+			return cloneVector(y)
+		}
+		return rectMatTVec(A, y)
+	}
+	normA := 0.0
+	if A != nil {
+		for _, row := range A {
+			normA += dotProd(row, row)
+		}
+	}
+	quad, isQuadratic := F.(QuadraticProx)
+	linearized := A != nil && !isQuadratic
+	rho := settings.Rho
+	x := cloneVector(x0)
+	z := apply(x)
+	u := make([]float64, len(z))
+	n, m := float64(len(x)), float64(len(z))
+	var primal, dual float64
+	for iter := 1; iter <= settings.MaxIter; iter++ {
+		switch {
+		case A == nil:
+			x = F.Prox(subVec(z, u), 1/rho)
+		case isQuadratic:
+			M := make([][]float64, len(x))
+			for i := range M {
+				M[i] = cloneVector(quad.Q[i])
+				for j := range M[i] {
+					for k := range A {
+						M[i][j] += rho * A[k][i] * A[k][j]
+					}
+				}
+			}
+			x = solveDenseSystem(M, addScaled(quad.B, applyT(subVec(z, u)), rho))
+		default:
+			mu := 1 / (rho * normA)
+			r := addVec(subVec(apply(x), z), u)
+			x = F.Prox(addScaled(x, applyT(r), -mu*rho), mu)
+		}
+		Ax := apply(x)
+		zPrev := z
+		z = G.Prox(addVec(Ax, u), 1/rho)
+		r := subVec(Ax, z)
+		u = addVec(u, r)
+		primal = vecNorm(r)
+		dual = rho * vecNorm(applyT(subVec(z, zPrev)))
+		epsPri := math.Sqrt(m)*settings.AbsTol + settings.RelTol*math.Max(vecNorm(Ax), vecNorm(z))
+		epsDual := math.Sqrt(n)*settings.AbsTol + settings.RelTol*rho*vecNorm(applyT(u))
+		if primal <= epsPri && dual <= epsDual {
+			return ADMMResult{X: x, Z: z, U: u, Iterations: iter, PrimalResidual: primal, DualResidual: dual, Converged: true}
+		}
+		// The linearized step size is tied to rho, so it keeps rho fixed.
+		if settings.AdaptiveRho && !linearized {
+			if primal > 10*dual {
+				rho *= 2
+				u = scaleVec(u, 0.5)
+			} else if dual > 10*primal {
+				rho /= 2
+				u = scaleVec(u, 2)
+			}
+		}
+	}
+	return ADMMResult{X: x, Z: z, U: u, Iterations: settings.MaxIter, PrimalResidual: primal, DualResidual: dual}
+}
+
+func ConsensusADMM(terms []ProxOperator, G ProxOperator, x0 []float64, settings ADMMSettings) ADMMResult {
+	if G == nil {
+		G = ZeroProx{}
+	}
+	N := len(terms)
+	rho := settings.Rho
+	z := cloneVector(x0)
+	xs := make([][]float64, N)
+	us := make([][]float64, N)
+	for i := range terms {
+		xs[i] = cloneVector(x0)
+		us[i] = make([]float64, len(x0))
+	}
+	n := float64(len(x0) * N)
+	var primal, dual float64
+	for iter := 1; iter <= settings.MaxIter; iter++ {
+		avg := make([]float64, len(z))
+		for i, term := range terms {
+			xs[i] = term.Prox(subVec(z, us[i]), 1/rho)
+			avg = addVec(avg, addVec(xs[i], us[i]))
+		}
+		avg = scaleVec(avg, 1/float64(N))
+		zPrev := z
+		z = G.Prox(avg, 1/(rho*float64(N)))
+		primalSq, xNormSq, uNormSq := 0.0, 0.0, 0.0
+		for i := range terms {
+			r := subVec(xs[i], z)
+			us[i] = addVec(us[i], r)
+			primalSq += dotProd(r, r)
+			xNormSq += dotProd(xs[i], xs[i])
+			uNormSq += dotProd(us[i], us[i])
+		}
+		primal = math.Sqrt(primalSq)
+		dual = rho * math.Sqrt(float64(N)) * vecNorm(subVec(z, zPrev))
+		epsPri := math.Sqrt(n)*settings.AbsTol + settings.RelTol*math.Max(math.Sqrt(xNormSq), math.Sqrt(float64(N))*vecNorm(z))
+		epsDual := math.Sqrt(n)*settings.AbsTol + settings.RelTol*rho*math.Sqrt(uNormSq)
+		if primal <= epsPri && dual <= epsDual {
+			return ADMMResult{X: z, Z: z, U: concatVectors(us), Iterations: iter, PrimalResidual: primal, DualResidual: dual, Converged: true}
+		}
+		if settings.AdaptiveRho {
+			if primal > 10*dual {
+				rho *= 2
+				for i := range us {
+					us[i] = scaleVec(us[i], 0.5)
+				}
+			} else if dual > 10*primal {
+				rho /= 2
+				for i := range us {
+					us[i] = scaleVec(us[i], 2)
+				}
+			}
+		}
+	}
+	return ADMMResult{X: z, Z: z, U: concatVectors(us), Iterations: settings.MaxIter, PrimalResidual: primal, DualResidual: dual}
+}
+
+func concatVectors(vs [][]float64) []float64 {
+	out := []float64{}
+	for _, v := range vs {
+		out = append(out, v...)
+	}
+	return out
+}
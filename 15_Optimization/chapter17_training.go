@@ -0,0 +1,325 @@
+// 2026 Update: Mini-Batch Training
+package optimization
+
+import "math"
+
+type BatchGrad func(x []float64, batch []int) []float64
+
+func SampleGradBatch(grad SampleGrad) BatchGrad {
+	return func(x []float64, batch []int) []float64 {
+		g := make([]float64, len(x))
+		for _, idx := range batch {
+			gi := grad(x, idx)
+			for i := range g {
+				g[i] += gi[i]
+			}
+		}
+		if len(batch) > 0 {
+			g = scaleVec(g, 1/float64(len(batch)))
+		}
+		return g
+	}
+}
+
+func ShuffledBatches(rng *RNG, dataSize, batchSize int) [][]int {
+	if batchSize <= 0 {
+		batchSize = dataSize
+	}
+	perm := RandomPermutation(rng, dataSize)
+	batches := [][]int{}
+	for start := 0; start < dataSize; start += batchSize {
+		end := start + batchSize
+		if end > dataSize {
+			end = dataSize
+		}
+		batches = append(batches, []int(perm[start:end]))
+	}
+	return batches
+}
+
+// LRSchedule gives the learning rate for a global step. Observe receives the
+// validation loss after each epoch and is a no-op for fixed schedules.
+type LRSchedule interface {
+	Rate(step, epoch int) float64
+	Observe(epoch int, loss float64)
+}
+
+type ConstantLR struct {
+	Value float64
+}
+
+func (s ConstantLR) Rate(step, epoch int) float64 {
+	return s.Value
+}
+
+func (ConstantLR) Observe(epoch int, loss float64) {}
+
+type StepLR struct {
+	Base       float64
+	Gamma      float64
+	StepEpochs int
+}
+
+func (s StepLR) Rate(step, epoch int) float64 {
+	if s.StepEpochs <= 0 {
+		return s.Base
+	}
+	return s.Base * math.Pow(s.Gamma, float64(epoch/s.StepEpochs))
+}
+
+func (StepLR) Observe(epoch int, loss float64) {}
+
+type CosineLR struct {
+	Base       float64
+	Min        float64
+	TotalSteps int
+}
+
+func (s CosineLR) Rate(step, epoch int) float64 {
+	if s.TotalSteps <= 0 || step >= s.TotalSteps {
+		return s.Min
+	}
+	return s.Min + 0.5*(s.Base-s.Min)*(1+math.Cos(math.Pi*float64(step)/float64(s.TotalSteps)))
+}
+
+func (CosineLR) Observe(epoch int, loss float64) {}
+
+type WarmupLR struct {
+	WarmupSteps int
+	After       LRSchedule
+}
+
+func (s WarmupLR) Rate(step, epoch int) float64 {
+	if step < s.WarmupSteps {
+		return s.After.Rate(0, epoch) * float64(step+1) / float64(s.WarmupSteps)
+	}
+	return s.After.Rate(step-s.WarmupSteps, epoch)
+}
+
+func (s WarmupLR) Observe(epoch int, loss float64) {
+	s.After.Observe(epoch, loss)
+}
+
+type PlateauLR struct {
+	Base      float64
+	Factor    float64
+	Patience  int
+	Threshold float64
+	MinRate   float64
+	current   float64
+	best      float64
+	wait      int
+	started   bool
+}
+
+func NewPlateauLR(base, factor float64, patience int) *PlateauLR {
+	return &PlateauLR{Base: base, Factor: factor, Patience: patience, Threshold: 1e-4, MinRate: 0}
+}
+
+func (s *PlateauLR) Rate(step, epoch int) float64 {
+	if !s.started {
+		return s.Base
+	}
+	return s.current
+}
+
+func (s *PlateauLR) Observe(epoch int, loss float64) {
+	if !s.started {
+		s.started = true
+		s.current = s.Base
+		s.best = loss
+		return
+	}
+	if loss < s.best-s.Threshold*absO(s.best) {
+		s.best = loss
+		s.wait = 0
+		return
+	}
+	s.wait++
+	if s.wait > s.Patience {
+		s.current = math.Max(s.current*s.Factor, s.MinRate)
+		s.wait = 0
+	}
+}
+
+type TrainingOptimizer int
+
+const (
+	TrainSGD TrainingOptimizer = iota
+	TrainMomentum
+	TrainNesterov
+	TrainAdaGrad
+	TrainRMSProp
+	TrainAdam
+	TrainAdamW
+	TrainNadam
+)
+
+type TrainingSettings struct {
+	Epochs      int
+	BatchSize   int
+	DataSize    int
+	Seed        uint64
+	Optimizer   TrainingOptimizer
+	Schedule    LRSchedule
+	Momentum    float64
+	Beta1       float64
+	Beta2       float64
+	Epsilon     float64
+	WeightDecay float64
+	ClipNorm    float64
+	ClipValue   float64
+	Validation  ObjectiveFunc
+	Patience    int
+	MinDelta    float64
+}
+
+func DefaultTrainingSettings(dataSize int) TrainingSettings {
+	return TrainingSettings{
+		Epochs:      50,
+		BatchSize:   32,
+		DataSize:    dataSize,
+		Seed:        42,
+		Optimizer:   TrainAdam,
+		Schedule:    ConstantLR{Value: 0.001},
+		Momentum:    0.9,
+		Beta1:       0.9,
+		Beta2:       0.999,
+		Epsilon:     1e-8,
+		WeightDecay: 0.01,
+		Patience:    10,
+		MinDelta:    0,
+	}
+}
+
+type TrainingResult struct {
+	X             []float64
+	Epochs        int
+	Steps         int
+	BestLoss      float64
+	BestEpoch     int
+	History       []float64
+	StoppedEarly  bool
+	LearningRates []float64
+}
+
+func ClipGradientNorm(g []float64, maxNorm float64) []float64 {
+	norm := vecNorm(g)
+	if maxNorm <= 0 || norm <= maxNorm {
+		return g
+	}
+	return scaleVec(g, maxNorm/norm)
+}
+
+func ClipGradientValue(g []float64, limit float64) []float64 {
+	if limit <= 0 {
+		return g
+	}
+	out := make([]float64, len(g))
+	for i, v := range g {
+		out[i] = clampValue(v, -limit, limit)
+	}
+	return out
+}
+
+type trainingState struct {
+	settings TrainingSettings
+	m        []float64
+	v        []float64
+	t        int
+}
+
+func (st *trainingState) step(x, g []float64, lr float64) {
+	s := st.settings
+	st.t++
+	t := float64(st.t)
+	switch s.Optimizer {
+	case TrainMomentum, TrainNesterov:
+		for i := range x {
+			st.m[i] = s.Momentum*st.m[i] + g[i]
+			if s.Optimizer == TrainNesterov {
+				x[i] -= lr * (g[i] + s.Momentum*st.m[i])
+			} else {
+				x[i] -= lr * st.m[i]
+			}
+		}
+	case TrainAdaGrad:
+		for i := range x {
+			st.v[i] += g[i] * g[i]
+			x[i] -= lr * g[i] / (math.Sqrt(st.v[i]) + s.Epsilon)
+		}
+	case TrainRMSProp:
+		for i := range x {
+			st.v[i] = s.Beta2*st.v[i] + (1-s.Beta2)*g[i]*g[i]
+			x[i] -= lr * g[i] / (math.Sqrt(st.v[i]) + s.Epsilon)
+		}
+	case TrainAdam, TrainAdamW, TrainNadam:
+		bias1 := 1 - math.Pow(s.Beta1, t)
+		bias2 := 1 - math.Pow(s.Beta2, t)
+		for i := range x {
+			st.m[i] = s.Beta1*st.m[i] + (1-s.Beta1)*g[i]
+			st.v[i] = s.Beta2*st.v[i] + (1-s.Beta2)*g[i]*g[i]
+			mhat := st.m[i] / bias1
+			if s.Optimizer == TrainNadam {
+				mhat = s.Beta1*mhat + (1-s.Beta1)*g[i]/bias1
+			}
+			update := mhat / (math.Sqrt(st.v[i]/bias2) + s.Epsilon)
+			if s.Optimizer == TrainAdamW {
+				update += s.WeightDecay * x[i]
+			}
+			x[i] -= lr * update
+		}
+	default:
+		for i := range x {
+			x[i] -= lr * g[i]
+		}
+	}
+}
+
+func TrainMiniBatch(grad BatchGrad, x0 []float64, settings TrainingSettings) TrainingResult {
+	rng := NewRNG(settings.Seed)
+	schedule := settings.Schedule
+	if schedule == nil {
+		schedule = ConstantLR{Value: 0.001}
+	}
+	x := cloneVector(x0)
+	st := &trainingState{settings: settings, m: make([]float64, len(x)), v: make([]float64, len(x))}
+	result := TrainingResult{X: cloneVector(x), BestLoss: math.Inf(1), BestEpoch: -1}
+	if settings.DataSize <= 0 {
+		return result
+	}
+	step := 0
+	wait := 0
+	for epoch := 0; epoch < settings.Epochs; epoch++ {
+		result.LearningRates = append(result.LearningRates, schedule.Rate(step, epoch))
+		for _, batch := range ShuffledBatches(rng, settings.DataSize, settings.BatchSize) {
+			g := grad(x, batch)
+			g = ClipGradientValue(g, settings.ClipValue)
+			g = ClipGradientNorm(g, settings.ClipNorm)
+			st.step(x, g, schedule.Rate(step, epoch))
+			step++
+		}
+		result.Epochs = epoch + 1
+		result.Steps = step
+		if settings.Validation == nil {
+			result.X = cloneVector(x)
+			continue
+		}
+		loss := settings.Validation(x)
+		result.History = append(result.History, loss)
+		schedule.Observe(epoch, loss)
+		if loss < result.BestLoss-settings.MinDelta {
+			result.BestLoss = loss
+			result.BestEpoch = epoch
+			result.X = cloneVector(x)
+			wait = 0
+			continue
+		}
+		wait++
+		if settings.Patience > 0 && wait >= settings.Patience {
+			result.StoppedEarly = true
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,292 @@
+// 2026 Update: L-BFGS-B
+package optimization
+
+import (
+	"math"
+	"sort"
+)
+
+type LBFGSBSettings struct {
+	MaxIter int
+	Tol     float64
+	FTol    float64
+	Memory  int
+	LineC1  float64
+	LineTau float64
+}
+
+func DefaultLBFGSBSettings() LBFGSBSettings {
+	return LBFGSBSettings{
+		MaxIter: 1000,
+		Tol:     1e-8,
+		FTol:    1e-15,
+		Memory:  6,
+		LineC1:  1e-4,
+		LineTau: 0.5,
+	}
+}
+
+// LBFGSB minimizes f over the box given by bounds; either side of a bound
+// may be ±Inf and nil bounds leave every variable free.
+func LBFGSB(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, bounds [][2]float64, settings LBFGSBSettings) []float64 {
+	n := len(x0)
+	if grad == nil {
+		grad = func(x []float64) []float64 {
+			return finiteDiffGradQ(f, x, 1e-7)
+		}
+	}
+	if bounds == nil {
+		bounds = uniformBounds(n, math.Inf(-1), math.Inf(1))
+	}
+	x := clampVector(x0, bounds)
+	fx := f(x)
+	g := grad(x)
+	var S, Y [][]float64
+	theta := 1.0
+	for iter := 0; iter < settings.MaxIter; iter++ {
+		if projectedGradientNorm(x, g, bounds) <= settings.Tol {
+			break
+		}
+		W, M := lbfgsbCompactForm(S, Y, theta)
+		xcp, c := generalizedCauchyPoint(x, g, bounds, theta, W, M)
+		d := subVec(lbfgsbSubspaceMin(x, g, xcp, c, bounds, theta, W, M), x)
+		if dotProd(g, d) >= 0 {
+			S, Y, theta = nil, nil, 1
+			d = subVec(clampVector(subVec(x, g), bounds), x)
+			if dotProd(g, d) >= 0 {
+				break
+			}
+		}
+		alpha := 1.0
+		if len(S) == 0 {
+			alpha = math.Min(1, 1/vecNorm(d))
+		}
+		xn, fn := x, fx
+		accepted := false
+		for k := 0; k < 40; k++ {
+			xn = clampVector(addScaled(x, d, alpha), bounds)
+			fn = f(xn)
+			if fn <= fx+settings.LineC1*dotProd(g, subVec(xn, x)) {
+				accepted = true
+				break
+			}
+			alpha *= settings.LineTau
+		}
+		if !accepted {
+			if len(S) == 0 {
+				break
+			}
+			S, Y, theta = nil, nil, 1
+			continue
+		}
+		gn := grad(xn)
+		s := subVec(xn, x)
+		y := subVec(gn, g)
+		if sy := dotProd(s, y); sy > 2.2e-16*dotProd(y, y) {
+			if len(S) == settings.Memory {
+				S, Y = S[1:], Y[1:]
+			}
+			S = append(S, s)
+			Y = append(Y, y)
+			theta = dotProd(y, y) / sy
+		}
+		fold := fx
+		x, fx, g = xn, fn, gn
+		if fold-fx <= settings.FTol*math.Max(math.Max(absO(fold), absO(fx)), 1) {
+			break
+		}
+	}
+	return x
+}
+
+func projectedGradientNorm(x, g []float64, bounds [][2]float64) float64 {
+	return vectorNormInf(subVec(clampVector(subVec(x, g), bounds), x))
+}
+
+// lbfgsbCompactForm returns W = [Y θS] (one row per variable) and the middle
+// matrix M of the compact representation B = θI - W M Wᵀ.
+func lbfgsbCompactForm(S, Y [][]float64, theta float64) ([][]float64, [][]float64) {
+	k := len(S)
+	if k == 0 {
+		return nil, nil
+	}
+	n := len(S[0])
+	W := make([][]float64, n)
+	for i := range W {
+		W[i] = make([]float64, 2*k)
+		for j := 0; j < k; j++ {
+			W[i][j] = Y[j][i]
+			W[i][k+j] = theta * S[j][i]
+		}
+	}
+	inner := make([][]float64, 2*k)
+	for i := range inner {
+		inner[i] = make([]float64, 2*k)
+	}
+	for i := 0; i < k; i++ {
+		inner[i][i] = -dotProd(S[i], Y[i])
+		for j := 0; j < k; j++ {
+			inner[k+i][k+j] = theta * dotProd(S[i], S[j])
+			if i > j {
+				l := dotProd(S[i], Y[j])
+				inner[k+i][j] = l
+				inner[j][k+i] = l
+			}
+		}
+	}
+	return W, invertDense(inner)
+}
+
+func generalizedCauchyPoint(x, g []float64, bounds [][2]float64, theta float64, W, M [][]float64) ([]float64, []float64) {
+	n := len(x)
+	m2 := len(M)
+	t := make([]float64, n)
+	d := make([]float64, n)
+	breaks := []int{}
+	for i := range x {
+		switch {
+		case g[i] < 0:
+			t[i] = (x[i] - bounds[i][1]) / g[i]
+		case g[i] > 0:
+			t[i] = (x[i] - bounds[i][0]) / g[i]
+		default:
+			t[i] = math.Inf(1)
+		}
+		if t[i] > 0 {
+			d[i] = -g[i]
+			if !math.IsInf(t[i], 1) {
+				breaks = append(breaks, i)
+			}
+		}
+	}
+	sort.Slice(breaks, func(a, b int) bool { return t[breaks[a]] < t[breaks[b]] })
+	row := func(i int) []float64 {
+		if m2 == 0 {
+			return nil
+		}
+		return W[i]
+	}
+	p := make([]float64, m2)
+	for i := range x {
+		if d[i] != 0 && m2 > 0 {
+			p = addScaled(p, W[i], d[i])
+		}
+	}
+	c := make([]float64, m2)
+	fp := -dotProd(d, d)
+	fpp := -theta*fp - dotProd(p, matVec(M, p))
+	fppMin := 2.2e-16 * -theta * fp
+	dtMin := math.Inf(1)
+	if fpp > 0 {
+		dtMin = -fp / fpp
+	}
+	xcp := cloneVector(x)
+	tOld := 0.0
+	for _, b := range breaks {
+		dt := t[b] - tOld
+		if dtMin < dt {
+			break
+		}
+		if d[b] > 0 {
+			xcp[b] = bounds[b][1]
+		} else {
+			xcp[b] = bounds[b][0]
+		}
+		zb := xcp[b] - x[b]
+		c = addScaled(c, p, dt)
+		wb := row(b)
+		gb := g[b]
+		fp += dt*fpp + gb*gb + theta*gb*zb
+		fpp -= theta * gb * gb
+		if m2 > 0 {
+			fp -= gb * dotProd(wb, matVec(M, c))
+			fpp -= 2*gb*dotProd(wb, matVec(M, p)) + gb*gb*dotProd(wb, matVec(M, wb))
+			p = addScaled(p, wb, gb)
+		}
+		fpp = math.Max(fppMin, fpp)
+		d[b] = 0
+		dtMin = -fp / fpp
+		tOld = t[b]
+	}
+	dtMin = math.Max(dtMin, 0)
+	if math.IsInf(dtMin, 1) {
+		dtMin = 0
+	}
+	tOld += dtMin
+	for i := range x {
+		if d[i] != 0 {
+			xcp[i] = x[i] + tOld*d[i]
+		}
+	}
+	return xcp, addScaled(c, p, dtMin)
+}
+
+// lbfgsbSubspaceMin minimizes the quadratic model over the variables that are
+// free at the Cauchy point and truncates the step to stay inside the box.
+func lbfgsbSubspaceMin(x, g, xcp, c []float64, bounds [][2]float64, theta float64, W, M [][]float64) []float64 {
+	free := []int{}
+	for i := range xcp {
+		if xcp[i] > bounds[i][0] && xcp[i] < bounds[i][1] {
+			free = append(free, i)
+		}
+	}
+	if len(free) == 0 {
+		return xcp
+	}
+	m2 := len(M)
+	var Mc []float64
+	if m2 > 0 {
+		Mc = matVec(M, c)
+	}
+	r := make([]float64, len(free))
+	for k, i := range free {
+		r[k] = g[i] + theta*(xcp[i]-x[i])
+		if m2 > 0 {
+			r[k] -= dotProd(W[i], Mc)
+		}
+	}
+	du := scaleVec(r, -1/theta)
+	if m2 > 0 {
+		wr := make([]float64, m2)
+		WtW := make([][]float64, m2)
+		for a := range WtW {
+			WtW[a] = make([]float64, m2)
+		}
+		for k, i := range free {
+			wr = addScaled(wr, W[i], r[k])
+			for a := 0; a < m2; a++ {
+				for b := 0; b < m2; b++ {
+					WtW[a][b] += W[i][a] * W[i][b]
+				}
+			}
+		}
+		MWtW := make([][]float64, m2)
+		for a := range MWtW {
+			MWtW[a] = make([]float64, m2)
+			for b := 0; b < m2; b++ {
+				for k := 0; k < m2; k++ {
+					MWtW[a][b] -= M[a][k] * WtW[k][b] / theta
+				}
+			}
+			MWtW[a][a] += 1
+		}
+		v := solveDenseSystem(MWtW, matVec(M, wr))
+		for k, i := range free {
+			du[k] -= dotProd(W[i], v) / (theta * theta)
+		}
+	}
+	alpha := 1.0
+	for k, i := range free {
+		switch {
+		case du[k] > 0:
+			alpha = math.Min(alpha, (bounds[i][1]-xcp[i])/du[k])
+		case du[k] < 0:
+			alpha = math.Min(alpha, (bounds[i][0]-xcp[i])/du[k])
+		}
+	}
+	xbar := cloneVector(xcp)
+	for k, i := range free {
+		xbar[i] += alpha * du[k]
+	}
+	return xbar
+}
@@ -0,0 +1,376 @@
+// 2026 Update: Checkpointing
+package optimization
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+type CheckpointFormat int
+
+const (
+	CheckpointJSON CheckpointFormat = iota
+	CheckpointBinary
+)
+
+type CheckpointState interface {
+	Save(w io.Writer, format CheckpointFormat) error
+	Load(r io.Reader, format CheckpointFormat) error
+}
+
+// CheckpointSettings calls Handler with the live optimizer state after every
+// Every completed iterations. The state is reused afterwards, so Handler must
+// finish saving it before returning.
+type CheckpointSettings struct {
+	Every   int
+	Handler func(state CheckpointState)
+}
+
+func (c CheckpointSettings) notify(iteration int, state CheckpointState) {
+	if c.Every > 0 && c.Handler != nil && iteration%c.Every == 0 {
+		c.Handler(state)
+	}
+}
+
+func (r *RNG) State() uint64 {
+	r.requireLCG("State")
+	return r.state
+}
+
+func (r *RNG) SetState(state uint64) {
+	r.requireLCG("SetState")
+	r.state = state
+}
+
+type PSOState struct {
+	Swarm           []Particle
+	GlobalBest      []float64
+	GlobalBestValue float64
+	BestPrev        float64
+	Stall           int
+	Iteration       int
+	RNG             uint64
+}
+
+type DEState struct {
+	Population [][]float64
+	Fitness    []float64
+	BestIdx    int
+	Generation int
+	RNG        uint64
+}
+
+type GAState struct {
+	Population [][]float64
+	Fitness    []float64
+	Generation int
+	RNG        uint64
+}
+
+type CEMState struct {
+	Mean      []float64
+	Std       []float64
+	Best      []float64
+	BestValue float64
+	Iteration int
+	RNG       uint64
+}
+
+type CMAESDiagonalState struct {
+	Mean      []float64
+	Diag      []float64
+	Sigma     float64
+	Best      []float64
+	BestValue float64
+	Iteration int
+	RNG       uint64
+}
+
+// CMAESFullState is the full CMA-ES between generations: the distribution of
+// the current run (mean, covariance C = B·diag(D)²·Bᵀ, evolution paths and
+// step size), its stopping history, and the restart bookkeeping across runs.
+type CMAESFullState struct {
+	Start        []float64
+	Mean         []float64
+	C            [][]float64
+	B            [][]float64
+	D            []float64
+	PC           []float64
+	PS           []float64
+	Sigma        float64
+	Lambda       int
+	History      []float64
+	RunBest      []float64
+	RunBestValue float64
+	RunBudget    int
+	RunDone      bool
+	Generation   int
+	Evaluations  int
+	EigenEval    int
+	Best         []float64
+	BestValue    float64
+	Used         int
+	Restarts     int
+	LargePop     int
+	Large        bool
+	LargeEvals   int
+	SmallEvals   int
+	Iteration    int
+	RNG          uint64
+}
+
+type AnnealState struct {
+	X            []float64
+	CurrentValue float64
+	Best         []float64
+	BestValue    float64
+	Temperature  float64
+	Level        int
+	RNG          uint64
+}
+
+type TabuState struct {
+	X         []float64
+	Best      []float64
+	BestValue float64
+	Memory    [][]float64
+	Iteration int
+	RNG       uint64
+}
+
+func (s *PSOState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *PSOState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func (s *DEState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *DEState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func (s *GAState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *GAState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func (s *CEMState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *CEMState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func (s *CMAESDiagonalState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *CMAESDiagonalState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func (s *CMAESFullState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *CMAESFullState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func (s *AnnealState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *AnnealState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func (s *TabuState) Save(w io.Writer, format CheckpointFormat) error {
+	return saveCheckpoint(w, s, format)
+}
+
+func (s *TabuState) Load(r io.Reader, format CheckpointFormat) error {
+	return loadCheckpoint(r, s, format)
+}
+
+func PSOResume(f ObjectiveFunc, bounds [][2]float64, state *PSOState, settings PSOSettings) []float64 {
+	return runPSO(f, bounds, state, settings)
+}
+
+func DifferentialEvolutionResume(f ObjectiveFunc, bounds [][2]float64, state *DEState, settings DESettings) []float64 {
+	return runDE(f, bounds, state, settings)
+}
+
+func GeneticAlgorithmResume(f ObjectiveFunc, bounds [][2]float64, state *GAState, settings GASettings) []float64 {
+	return runGA(f, bounds, state, settings)
+}
+
+func CrossEntropyMethodResume(f ObjectiveFunc, state *CEMState, settings CEMSettings) []float64 {
+	return runCEM(f, state, settings)
+}
+
+func CMAESDiagonalResume(f ObjectiveFunc, state *CMAESDiagonalState, settings CMAESSettings) []float64 {
+	return runCMAESDiagonal(f, state, settings)
+}
+
+func CMAESFullResume(f ObjectiveFunc, state *CMAESFullState, settings CMAESFullSettings) []float64 {
+	return runCMAESFull(f, state, settings)
+}
+
+func SimulatedAnnealingResume(f ObjectiveFunc, bounds [][2]float64, state *AnnealState, settings AnnealSettings) []float64 {
+	return runAnnealing(f, bounds, state, settings)
+}
+
+func TabuSearchResume(f ObjectiveFunc, bounds [][2]float64, state *TabuState, settings TabuSettings) []float64 {
+	return runTabu(f, bounds, state, settings)
+}
+
+func saveCheckpoint(w io.Writer, state any, format CheckpointFormat) error {
+	if format == CheckpointBinary {
+		return gob.NewEncoder(w).Encode(state)
+	}
+	return json.NewEncoder(w).Encode(checkpointToJSON(reflect.ValueOf(state)))
+}
+
+func loadCheckpoint(r io.Reader, state any, format CheckpointFormat) error {
+	if format == CheckpointBinary {
+		return gob.NewDecoder(r).Decode(state)
+	}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	return checkpointFromJSON(raw, reflect.ValueOf(state).Elem())
+}
+
+// checkpointToJSON mirrors the state as plain JSON values, writing floats with
+// the shortest round-trip representation and non-finite values as strings,
+// which encoding/json rejects.
+func checkpointToJSON(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Pointer:
+		return checkpointToJSON(v.Elem())
+	case reflect.Struct:
+		out := map[string]any{}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out[v.Type().Field(i).Name] = checkpointToJSON(v.Field(i))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = checkpointToJSON(v.Index(i))
+		}
+		return out
+	case reflect.Float64:
+		x := v.Float()
+		switch {
+		case math.IsNaN(x):
+			return "NaN"
+		case math.IsInf(x, 1):
+			return "+Inf"
+		case math.IsInf(x, -1):
+			return "-Inf"
+		}
+		return json.Number(strconv.FormatFloat(x, 'g', -1, 64))
+	case reflect.Uint64:
+		return json.Number(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Int:
+		return json.Number(strconv.FormatInt(v.Int(), 10))
+	default:
+		return v.Interface()
+	}
+}
+
+func checkpointFromJSON(data any, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		fields, ok := data.(map[string]any)
+		if !ok {
+			return fmt.Errorf("checkpoint: expected object for %s", v.Type())
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if raw, present := fields[field.Name]; present && field.IsExported() {
+				if err := checkpointFromJSON(raw, v.Field(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice:
+		if data == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		items, ok := data.([]any)
+		if !ok {
+			return fmt.Errorf("checkpoint: expected array for %s", v.Type())
+		}
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := checkpointFromJSON(item, out.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+	case reflect.Float64:
+		var x float64
+		var err error
+		switch raw := data.(type) {
+		case json.Number:
+			x, err = strconv.ParseFloat(string(raw), 64)
+		case string:
+			x, err = strconv.ParseFloat(raw, 64)
+		default:
+			err = fmt.Errorf("checkpoint: expected number, got %v", data)
+		}
+		if err != nil {
+			return err
+		}
+		v.SetFloat(x)
+	case reflect.Uint64:
+		raw, _ := data.(json.Number)
+		x, err := strconv.ParseUint(string(raw), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(x)
+	case reflect.Int:
+		raw, _ := data.(json.Number)
+		x, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(x)
+	case reflect.Bool:
+		b, ok := data.(bool)
+		if !ok {
+			return fmt.Errorf("checkpoint: expected bool, got %v", data)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("checkpoint: unsupported kind %s", v.Kind())
+	}
+	return nil
+}
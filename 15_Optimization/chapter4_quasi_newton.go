@@ -105,11 +105,13 @@ func ConjugateGradientDiagonalPrecond(A [][]float64, b []float64, settings CGSet
 }
 
 type NLCGSettings struct {
-	MaxIter  int
-	Tol      float64
-	LineC1   float64
-	LineTau  float64
-	MethodPR bool
+	MaxIter    int
+	Tol        float64
+	LineC1     float64
+	LineTau    float64
+	MethodPR   bool
+	LineSearch LineSearchMethod
+	LineC2     float64
 }
 
 func DefaultNLCGSettings() NLCGSettings {
@@ -119,12 +121,14 @@ func DefaultNLCGSettings() NLCGSettings {
 		LineC1:   1e-4,
 		LineTau:  0.5,
 		MethodPR: true,
+		LineC2:   0.1,
 	}
 }
 
 func NonlinearConjugateGradient(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, settings NLCGSettings) []float64 {
 	x := cloneVec(x0)
 	g := grad(x)
+	fx := math.NaN()
 	p := make([]float64, len(x))
 	for i := range x {
 		p[i] = -g[i]
@@ -133,11 +137,12 @@ func NonlinearConjugateGradient(f ObjectiveFunc, grad func([]float64) []float64,
 		if vecNorm(g) <= settings.Tol {
 			break
 		}
-		alpha := lineSearchArmijo(f, x, p, settings.LineC1, settings.LineTau)
+		ls := quasiNewtonLineSearch(settings.LineSearch, f, grad, x, p, g, fx, settings.LineC1, settings.LineC2, settings.LineTau)
 		for i := range x {
-			x[i] += alpha * p[i]
+			x[i] += ls.Alpha * p[i]
 		}
-		gNew := grad(x)
+		gNew := ls.Grad
+		fx = ls.F
 		beta := 0.0
 		if settings.MethodPR {
 			diff := make([]float64, len(x))
@@ -160,10 +165,12 @@ func NonlinearConjugateGradient(f ObjectiveFunc, grad func([]float64) []float64,
 }
 
 type BFGSSettings struct {
-	MaxIter int
-	Tol     float64
-	LineC1  float64
-	LineTau float64
+	MaxIter    int
+	Tol        float64
+	LineC1     float64
+	LineTau    float64
+	LineSearch LineSearchMethod
+	LineC2     float64
 }
 
 func DefaultBFGSSettings() BFGSSettings {
@@ -172,6 +179,7 @@ func DefaultBFGSSettings() BFGSSettings {
 		Tol:     1e-8,
 		LineC1:  1e-4,
 		LineTau: 0.5,
+		LineC2:  0.9,
 	}
 }
 
@@ -186,6 +194,7 @@ func BFGSWithSettings(f ObjectiveFunc, grad func([]float64) []float64, x0 []floa
 	x := cloneVec(x0)
 	H := identityMat(n)
 	g := grad(x)
+	fx := math.NaN()
 	for iter := 0; iter < settings.MaxIter; iter++ {
 		if vecNorm(g) < settings.Tol {
 			break
@@ -196,13 +205,14 @@ func BFGSWithSettings(f ObjectiveFunc, grad func([]float64) []float64, x0 []floa
 				p[i] -= H[i][j] * g[j]
 			}
 		}
-		alpha := lineSearchArmijo(f, x, p, settings.LineC1, settings.LineTau)
+		ls := quasiNewtonLineSearch(settings.LineSearch, f, grad, x, p, g, fx, settings.LineC1, settings.LineC2, settings.LineTau)
 		s := make([]float64, n)
 		for i := 0; i < n; i++ {
-			s[i] = alpha * p[i]
+			s[i] = ls.Alpha * p[i]
 			x[i] += s[i]
 		}
-		gNew := grad(x)
+		gNew := ls.Grad
+		fx = ls.F
 		y := make([]float64, n)
 		for i := 0; i < n; i++ {
 			y[i] = gNew[i] - g[i]
@@ -222,15 +232,17 @@ func DFP(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, settings
 	x := cloneVec(x0)
 	H := identityMat(n)
 	g := grad(x)
+	fx := math.NaN()
 	for iter := 0; iter < settings.MaxIter; iter++ {
 		if vecNorm(g) < settings.Tol {
 			break
 		}
 		p := matVec(H, scaleVec(g, -1))
-		alpha := lineSearchArmijo(f, x, p, settings.LineC1, settings.LineTau)
-		s := scaleVec(p, alpha)
+		ls := quasiNewtonLineSearch(settings.LineSearch, f, grad, x, p, g, fx, settings.LineC1, settings.LineC2, settings.LineTau)
+		s := scaleVec(p, ls.Alpha)
 		x = addVec(x, s)
-		gNew := grad(x)
+		gNew := ls.Grad
+		fx = ls.F
 		y := subVec(gNew, g)
 		sy := dotProd(s, y)
 		yHy := dotProd(y, matVec(H, y))
@@ -253,15 +265,17 @@ func SR1(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, settings
 	x := cloneVec(x0)
 	H := identityMat(n)
 	g := grad(x)
+	fx := math.NaN()
 	for iter := 0; iter < settings.MaxIter; iter++ {
 		if vecNorm(g) < settings.Tol {
 			break
 		}
 		p := matVec(H, scaleVec(g, -1))
-		alpha := lineSearchArmijo(f, x, p, settings.LineC1, settings.LineTau)
-		s := scaleVec(p, alpha)
+		ls := quasiNewtonLineSearch(settings.LineSearch, f, grad, x, p, g, fx, settings.LineC1, settings.LineC2, settings.LineTau)
+		s := scaleVec(p, ls.Alpha)
 		x = addVec(x, s)
-		gNew := grad(x)
+		gNew := ls.Grad
+		fx = ls.F
 		y := subVec(gNew, g)
 		Hs := matVec(H, y)
 		u := subVec(s, Hs)
@@ -280,11 +294,13 @@ func SR1(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, settings
 }
 
 type LBFGSSettings struct {
-	MaxIter int
-	Tol     float64
-	Memory  int
-	LineC1  float64
-	LineTau float64
+	MaxIter    int
+	Tol        float64
+	Memory     int
+	LineC1     float64
+	LineTau    float64
+	LineSearch LineSearchMethod
+	LineC2     float64
 }
 
 func DefaultLBFGSSettings() LBFGSSettings {
@@ -294,12 +310,14 @@ func DefaultLBFGSSettings() LBFGSSettings {
 		Memory:  6,
 		LineC1:  1e-4,
 		LineTau: 0.5,
+		LineC2:  0.9,
 	}
 }
 
 func LBFGS(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, settings LBFGSSettings) []float64 {
 	x := cloneVec(x0)
 	g := grad(x)
+	fx := math.NaN()
 	sList := make([][]float64, 0, settings.Memory)
 	yList := make([][]float64, 0, settings.Memory)
 	rhoList := make([]float64, 0, settings.Memory)
@@ -319,10 +337,11 @@ func LBFGS(f ObjectiveFunc, grad func([]float64) []float64, x0 []float64, settin
 			r = addVec(r, scaleVec(sList[i], alpha[i]-beta))
 		}
 		p := r
-		step := lineSearchArmijo(f, x, p, settings.LineC1, settings.LineTau)
-		s := scaleVec(p, step)
+		ls := quasiNewtonLineSearch(settings.LineSearch, f, grad, x, p, g, fx, settings.LineC1, settings.LineC2, settings.LineTau)
+		s := scaleVec(p, ls.Alpha)
 		x = addVec(x, s)
-		gNew := grad(x)
+		gNew := ls.Grad
+		fx = ls.F
 		y := subVec(gNew, g)
 		sy := dotProd(s, y)
 		if sy != 0 {
@@ -354,6 +373,235 @@ func lineSearchArmijo(f ObjectiveFunc, x, p []float64, c1, tau float64) float64
 	return alpha
 }
 
+type LineSearchMethod int
+
+const (
+	LineSearchArmijo LineSearchMethod = iota
+	LineSearchStrongWolfe
+)
+
+type LineSearchResult struct {
+	Alpha       float64
+	F           float64
+	Grad        []float64
+	Evaluations int
+	Converged   bool
+}
+
+// quasiNewtonLineSearch returns the step along p with the gradient at the new
+// point, and f there when the strong Wolfe search found it (NaN otherwise).
+// fx is f(x) from the previous step, or NaN when unknown. A strong Wolfe
+// search that fails to converge falls back to backtracking, so a failed
+// search is never repeated from the same point.
+func quasiNewtonLineSearch(method LineSearchMethod, f ObjectiveFunc, grad func([]float64) []float64, x, p, g []float64, fx, c1, c2, tau float64) LineSearchResult {
+	if method == LineSearchStrongWolfe && dotProd(g, p) < 0 {
+		if math.IsNaN(fx) {
+			fx = f(x)
+		}
+		if res := MoreThuenteLineSearch(f, grad, x, p, fx, g, c1, c2); res.Converged {
+			return res
+		}
+	}
+	alpha := lineSearchArmijo(f, x, p, c1, tau)
+	return LineSearchResult{Alpha: alpha, F: math.NaN(), Grad: grad(addVec(x, scaleVec(p, alpha)))}
+}
+
+// MoreThuenteLineSearch finds a step along the descent direction p that
+// satisfies the strong Wolfe conditions, following the MINPACK-2 dcsrch
+// safeguarded cubic/quadratic interval scheme.
+func MoreThuenteLineSearch(f ObjectiveFunc, grad func([]float64) []float64, x, p []float64, fx float64, gx []float64, c1, c2 float64) LineSearchResult {
+	const (
+		xtol     = 1e-12
+		stpMax   = 1e10
+		maxEvals = 40
+	)
+	finit := fx
+	ginit := dotProd(gx, p)
+	if ginit >= 0 {
+		return LineSearchResult{Alpha: 0, F: fx, Grad: cloneVec(gx)}
+	}
+	gtest := c1 * ginit
+	width := stpMax
+	width1 := 2 * width
+	lo := mtEndpoint{stp: 0, f: finit, g: ginit}
+	hi := lo
+	brackt := false
+	stage1 := true
+	stp := 1.0
+	stmin, stmax := 0.0, stp+4*stp
+	best := LineSearchResult{Alpha: 0, F: finit, Grad: cloneVec(gx)}
+	for evals := 1; evals <= maxEvals; evals++ {
+		xn := addVec(x, scaleVec(p, stp))
+		fp := f(xn)
+		gn := grad(xn)
+		dp := dotProd(gn, p)
+		if fp < best.F {
+			best = LineSearchResult{Alpha: stp, F: fp, Grad: gn}
+		}
+		best.Evaluations = evals
+		ftest := finit + stp*gtest
+		if stage1 && fp <= ftest && dp >= 0 {
+			stage1 = false
+		}
+		if fp <= ftest && absO(dp) <= -c2*ginit {
+			return LineSearchResult{Alpha: stp, F: fp, Grad: gn, Evaluations: evals, Converged: true}
+		}
+		if brackt && (stp <= stmin || stp >= stmax || stmax-stmin <= xtol*stmax) {
+			break
+		}
+		if stp == stpMax && fp <= ftest && dp <= gtest {
+			break
+		}
+		cur := mtEndpoint{stp: stp, f: fp, g: dp}
+		if stage1 && fp <= lo.f && fp > ftest {
+			// Modified function psi(a) = f(a) - a*gtest until the interval
+			// contains a point with sufficient decrease and non-negative slope.
+			shift := func(e mtEndpoint) mtEndpoint {
+				return mtEndpoint{stp: e.stp, f: e.f - e.stp*gtest, g: e.g - gtest}
+			}
+			unshift := func(e mtEndpoint) mtEndpoint {
+				return mtEndpoint{stp: e.stp, f: e.f + e.stp*gtest, g: e.g + gtest}
+			}
+			mlo, mhi := shift(lo), shift(hi)
+			stp = mtStep(&mlo, &mhi, shift(cur), &brackt, stmin, stmax)
+			lo, hi = unshift(mlo), unshift(mhi)
+		} else {
+			stp = mtStep(&lo, &hi, cur, &brackt, stmin, stmax)
+		}
+		if brackt {
+			if absO(hi.stp-lo.stp) >= 0.66*width1 {
+				stp = lo.stp + 0.5*(hi.stp-lo.stp)
+			}
+			width1 = width
+			width = absO(hi.stp - lo.stp)
+			stmin = math.Min(lo.stp, hi.stp)
+			stmax = math.Max(lo.stp, hi.stp)
+		} else {
+			stmin = stp + 1.1*(stp-lo.stp)
+			stmax = stp + 4*(stp-lo.stp)
+		}
+		stp = math.Max(math.Min(stp, stpMax), 0)
+		if brackt && (stp <= stmin || stp >= stmax || stmax-stmin <= xtol*stmax) {
+			stp = lo.stp
+		}
+	}
+	return best
+}
+
+type mtEndpoint struct {
+	stp float64
+	f   float64
+	g   float64
+}
+
+// mtStep is MINPACK-2 dcstep: it picks the next trial step from the best
+// point lo, the other endpoint hi and the new trial t, then updates the
+// interval of uncertainty.
+func mtStep(lo, hi *mtEndpoint, t mtEndpoint, brackt *bool, stpmin, stpmax float64) float64 {
+	sgnd := t.g * (lo.g / absO(lo.g))
+	var stpf float64
+	switch {
+	case t.f > lo.f:
+		theta := 3*(lo.f-t.f)/(t.stp-lo.stp) + lo.g + t.g
+		s := math.Max(absO(theta), math.Max(absO(lo.g), absO(t.g)))
+		gamma := s * math.Sqrt(math.Max((theta/s)*(theta/s)-(lo.g/s)*(t.g/s), 0))
+		if t.stp < lo.stp {
+			gamma = -gamma
+		}
+		p := (gamma - lo.g) + theta
+		q := ((gamma - lo.g) + gamma) + t.g
+		stpc := lo.stp + (p/q)*(t.stp-lo.stp)
+		stpq := lo.stp + ((lo.g/((lo.f-t.f)/(t.stp-lo.stp)+lo.g))/2)*(t.stp-lo.stp)
+		if absO(stpc-lo.stp) < absO(stpq-lo.stp) {
+			stpf = stpc
+		} else {
+			stpf = stpc + (stpq-stpc)/2
+		}
+		*brackt = true
+	case sgnd < 0:
+		theta := 3*(lo.f-t.f)/(t.stp-lo.stp) + lo.g + t.g
+		s := math.Max(absO(theta), math.Max(absO(lo.g), absO(t.g)))
+		gamma := s * math.Sqrt(math.Max((theta/s)*(theta/s)-(lo.g/s)*(t.g/s), 0))
+		if t.stp > lo.stp {
+			gamma = -gamma
+		}
+		p := (gamma - t.g) + theta
+		q := ((gamma - t.g) + gamma) + lo.g
+		stpc := t.stp + (p/q)*(lo.stp-t.stp)
+		stpq := t.stp + (t.g/(t.g-lo.g))*(lo.stp-t.stp)
+		if absO(stpc-t.stp) > absO(stpq-t.stp) {
+			stpf = stpc
+		} else {
+			stpf = stpq
+		}
+		*brackt = true
+	case absO(t.g) < absO(lo.g):
+		theta := 3*(lo.f-t.f)/(t.stp-lo.stp) + lo.g + t.g
+		s := math.Max(absO(theta), math.Max(absO(lo.g), absO(t.g)))
+		gamma := s * math.Sqrt(math.Max((theta/s)*(theta/s)-(lo.g/s)*(t.g/s), 0))
+		if t.stp > lo.stp {
+			gamma = -gamma
+		}
+		p := (gamma - t.g) + theta
+		q := (gamma + (lo.g - t.g)) + gamma
+		r := p / q
+		var stpc float64
+		switch {
+		case r < 0 && gamma != 0:
+			stpc = t.stp + r*(lo.stp-t.stp)
+		case t.stp > lo.stp:
+			stpc = stpmax
+		default:
+			stpc = stpmin
+		}
+		stpq := t.stp + (t.g/(t.g-lo.g))*(lo.stp-t.stp)
+		if *brackt {
+			if absO(stpc-t.stp) < absO(stpq-t.stp) {
+				stpf = stpc
+			} else {
+				stpf = stpq
+			}
+			if t.stp > lo.stp {
+				stpf = math.Min(t.stp+0.66*(hi.stp-t.stp), stpf)
+			} else {
+				stpf = math.Max(t.stp+0.66*(hi.stp-t.stp), stpf)
+			}
+		} else {
+			if absO(stpc-t.stp) > absO(stpq-t.stp) {
+				stpf = stpc
+			} else {
+				stpf = stpq
+			}
+			stpf = math.Max(stpmin, math.Min(stpmax, stpf))
+		}
+	default:
+		if *brackt {
+			theta := 3*(t.f-hi.f)/(hi.stp-t.stp) + hi.g + t.g
+			s := math.Max(absO(theta), math.Max(absO(hi.g), absO(t.g)))
+			gamma := s * math.Sqrt(math.Max((theta/s)*(theta/s)-(hi.g/s)*(t.g/s), 0))
+			if t.stp > hi.stp {
+				gamma = -gamma
+			}
+			p := (gamma - t.g) + theta
+			q := ((gamma - t.g) + gamma) + hi.g
+			stpf = t.stp + (p/q)*(hi.stp-t.stp)
+		} else if t.stp > lo.stp {
+			stpf = stpmax
+		} else {
+			stpf = stpmin
+		}
+	}
+	if t.f > lo.f {
+		*hi = t
+	} else {
+		if sgnd < 0 {
+			*hi = *lo
+		}
+		*lo = t
+	}
+	return stpf
+}
+
 func finiteDiffGradQ(f ObjectiveFunc, x []float64, h float64) []float64 {
 	if h == 0 {
 		h = 1e-6
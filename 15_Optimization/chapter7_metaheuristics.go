@@ -1,10 +1,16 @@
 // 2026 Update: Metaheuristics
 package optimization
 
-import "math"
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
 
 type RNG struct {
-	state uint64
+	state  uint64
+	source rand.Source
 }
 
 func NewRNG(seed uint64) *RNG {
@@ -14,11 +20,25 @@ func NewRNG(seed uint64) *RNG {
 	return &RNG{state: seed}
 }
 
+// NewRNGFromSource draws from src, such as a 16_Random generator, instead of
+// the built-in LCG. Its position lives in src, so Derive, State and SetState
+// panic on the result; split or checkpoint src itself instead.
+func NewRNGFromSource(src rand.Source) *RNG {
+	return &RNG{source: src}
+}
+
 func (r *RNG) Next() uint64 {
+	if r.source != nil {
+		return r.source.Uint64()
+	}
 	r.state = r.state*6364136223846793005 + 1442695040888963407
 	return r.state
 }
 
+func (r *RNG) Uint64() uint64 {
+	return r.Next()
+}
+
 func (r *RNG) Float64() float64 {
 	return float64(r.Next()>>11) / float64(1<<53)
 }
@@ -34,6 +54,59 @@ func (r *RNG) Range(low, high float64) float64 {
 	return low + r.Float64()*(high-low)
 }
 
+func (r *RNG) Derive(stream uint64) *RNG {
+	r.requireLCG("Derive")
+	return NewRNG(splitMix64(r.state ^ splitMix64(stream+0x9E3779B97F4A7C15)))
+}
+
+func (r *RNG) requireLCG(method string) {
+	if r.source != nil {
+		panic("optimization: RNG." + method + " is not supported on an RNG from NewRNGFromSource")
+	}
+}
+
+func splitMix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
+func parallelFor(n, workers int, body func(i int)) {
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			body(i)
+		}
+		return
+	}
+	if workers > n {
+		workers = n
+	}
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				body(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func evaluatePopulation(f ObjectiveFunc, pop [][]float64, workers int) []float64 {
+	values := make([]float64, len(pop))
+	parallelFor(len(pop), workers, func(i int) {
+		values[i] = f(pop[i])
+	})
+	return values
+}
+
 func clampValue(x, low, high float64) float64 {
 	if x < low {
 		return low
@@ -92,6 +165,13 @@ type PSOSettings struct {
 	WarmStart      [][]float64
 	InitialSeed    uint64
 	MinImprovement float64
+	// Workers <= 1 runs the classic asynchronous swarm on one RNG sequence.
+	// Workers > 1 switches to synchronous iterations, particle i drawing from
+	// stream i of the iteration, evaluated by that many goroutines. This is a
+	// different algorithm from the serial one, so its results differ from
+	// Workers <= 1, but they are identical for every worker count above 1.
+	Workers    int
+	Checkpoint CheckpointSettings
 }
 
 func DefaultPSOSettings() PSOSettings {
@@ -106,6 +186,7 @@ func DefaultPSOSettings() PSOSettings {
 		WarmStart:      nil,
 		InitialSeed:    42,
 		MinImprovement: 1e-12,
+		Workers:        0,
 	}
 }
 
@@ -116,10 +197,15 @@ func PSO(f ObjectiveFunc, dim, particles, iterations int, bounds [][2]float64) [
 }
 
 func PSOWithSettings(f ObjectiveFunc, dim, particles int, bounds [][2]float64, settings PSOSettings) []float64 {
+	return runPSO(f, bounds, newPSOState(f, dim, particles, bounds, settings), settings)
+}
+
+func newPSOState(f ObjectiveFunc, dim, particles int, bounds [][2]float64, settings PSOSettings) *PSOState {
 	rng := NewRNG(settings.InitialSeed)
 	swarm := make([]Particle, particles)
 	globalBest := make([]float64, dim)
 	globalBestValue := math.Inf(1)
+	positions := make([][]float64, particles)
 	for i := 0; i < particles; i++ {
 		pos := randomVector(rng, dim, bounds)
 		if settings.WarmStart != nil && i < len(settings.WarmStart) {
@@ -134,72 +220,109 @@ func PSOWithSettings(f ObjectiveFunc, dim, particles int, bounds [][2]float64, s
 			Position:     pos,
 			Velocity:     vel,
 			BestPosition: cloneVector(pos),
-			BestValue:    f(pos),
 		}
+		positions[i] = pos
+	}
+	initial := evaluatePopulation(f, positions, settings.Workers)
+	for i := 0; i < particles; i++ {
+		swarm[i].BestValue = initial[i]
 		if swarm[i].BestValue < globalBestValue {
 			globalBestValue = swarm[i].BestValue
-			vectorCopy(globalBest, pos)
+			vectorCopy(globalBest, swarm[i].Position)
 		}
 	}
-	bestPrev := globalBestValue
-	stall := 0
-	for iter := 0; iter < settings.Iterations; iter++ {
-		for i := 0; i < particles; i++ {
-			localBest := globalBest
-			if settings.Neighborhood > 0 {
-				bestIdx := i
-				bestVal := swarm[i].BestValue
-				start := i - settings.Neighborhood
-				end := i + settings.Neighborhood
-				for j := start; j <= end; j++ {
-					idx := j
-					if idx < 0 {
-						idx += particles
-					}
-					if idx >= particles {
-						idx -= particles
-					}
-					if swarm[idx].BestValue < bestVal {
-						bestVal = swarm[idx].BestValue
-						bestIdx = idx
-					}
-				}
-				localBest = swarm[bestIdx].BestPosition
-			}
-			for d := 0; d < dim; d++ {
-				r1 := rng.Float64()
-				r2 := rng.Float64()
-				vel := settings.Inertia*swarm[i].Velocity[d] + settings.Cognitive*r1*(swarm[i].BestPosition[d]-swarm[i].Position[d]) + settings.Social*r2*(localBest[d]-swarm[i].Position[d])
-				if settings.VelocityClamp > 0 {
-					vel = clampValue(vel, -settings.VelocityClamp, settings.VelocityClamp)
-				}
-				swarm[i].Velocity[d] = vel
-				swarm[i].Position[d] += vel
-				if settings.PositionClamp {
-					swarm[i].Position[d] = clampValue(swarm[i].Position[d], bounds[d][0], bounds[d][1])
-				}
+	return &PSOState{
+		Swarm:           swarm,
+		GlobalBest:      globalBest,
+		GlobalBestValue: globalBestValue,
+		BestPrev:        globalBestValue,
+		RNG:             rng.State(),
+	}
+}
+
+func runPSO(f ObjectiveFunc, bounds [][2]float64, s *PSOState, settings PSOSettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	swarm := s.Swarm
+	particles := len(swarm)
+	values := make([]float64, particles)
+	for s.Iteration < settings.Iterations && s.Stall <= 50 {
+		if settings.Workers > 1 {
+			rng.Next()
+			parallelFor(particles, settings.Workers, func(i int) {
+				stream := rng.Derive(uint64(i))
+				psoMove(stream, &swarm[i], psoLocalBest(swarm, s.GlobalBest, i, settings.Neighborhood), bounds, settings)
+				values[i] = f(swarm[i].Position)
+			})
+			for i := 0; i < particles; i++ {
+				psoRecord(&swarm[i], values[i], s.GlobalBest, &s.GlobalBestValue)
 			}
-			val := f(swarm[i].Position)
-			if val < swarm[i].BestValue {
-				swarm[i].BestValue = val
-				vectorCopy(swarm[i].BestPosition, swarm[i].Position)
-				if val < globalBestValue {
-					globalBestValue = val
-					vectorCopy(globalBest, swarm[i].Position)
-				}
+		} else {
+			for i := 0; i < particles; i++ {
+				psoMove(rng, &swarm[i], psoLocalBest(swarm, s.GlobalBest, i, settings.Neighborhood), bounds, settings)
+				psoRecord(&swarm[i], f(swarm[i].Position), s.GlobalBest, &s.GlobalBestValue)
 			}
 		}
-		if bestPrev-globalBestValue < settings.MinImprovement {
-			stall++
+		if s.BestPrev-s.GlobalBestValue < settings.MinImprovement {
+			s.Stall++
 		} else {
-			stall = 0
-			bestPrev = globalBestValue
+			s.Stall = 0
+			s.BestPrev = s.GlobalBestValue
 		}
-		if stall > 50 {
-			break
+		s.Iteration++
+		s.RNG = rng.State()
+		settings.Checkpoint.notify(s.Iteration, s)
+	}
+	return s.GlobalBest
+}
+
+func psoLocalBest(swarm []Particle, globalBest []float64, i, neighborhood int) []float64 {
+	if neighborhood <= 0 {
+		return globalBest
+	}
+	particles := len(swarm)
+	bestIdx := i
+	bestVal := swarm[i].BestValue
+	for j := i - neighborhood; j <= i+neighborhood; j++ {
+		idx := j
+		if idx < 0 {
+			idx += particles
+		}
+		if idx >= particles {
+			idx -= particles
+		}
+		if swarm[idx].BestValue < bestVal {
+			bestVal = swarm[idx].BestValue
+			bestIdx = idx
+		}
+	}
+	return swarm[bestIdx].BestPosition
+}
+
+func psoMove(rng *RNG, p *Particle, localBest []float64, bounds [][2]float64, settings PSOSettings) {
+	for d := range p.Position {
+		r1 := rng.Float64()
+		r2 := rng.Float64()
+		vel := settings.Inertia*p.Velocity[d] + settings.Cognitive*r1*(p.BestPosition[d]-p.Position[d]) + settings.Social*r2*(localBest[d]-p.Position[d])
+		if settings.VelocityClamp > 0 {
+			vel = clampValue(vel, -settings.VelocityClamp, settings.VelocityClamp)
+		}
+		p.Velocity[d] = vel
+		p.Position[d] += vel
+		if settings.PositionClamp {
+			p.Position[d] = clampValue(p.Position[d], bounds[d][0], bounds[d][1])
+		}
+	}
+}
+
+func psoRecord(p *Particle, val float64, globalBest []float64, globalBestValue *float64) {
+	if val < p.BestValue {
+		p.BestValue = val
+		vectorCopy(p.BestPosition, p.Position)
+		if val < *globalBestValue {
+			*globalBestValue = val
+			vectorCopy(globalBest, p.Position)
 		}
 	}
-	return globalBest
 }
 
 type DEStrategy int
@@ -217,6 +340,13 @@ type DESettings struct {
 	CR          float64
 	Strategy    DEStrategy
 	Seed        uint64
+	// Workers <= 1 runs the classic DE, replacing each member in place as soon
+	// as its trial wins. Workers > 1 switches to generational replacement,
+	// trial i drawing from stream i of the generation, evaluated by that many
+	// goroutines. Its results differ from Workers <= 1 but are identical for
+	// every worker count above 1.
+	Workers    int
+	Checkpoint CheckpointSettings
 }
 
 func DefaultDESettings(population, generations int) DESettings {
@@ -227,6 +357,7 @@ func DefaultDESettings(population, generations int) DESettings {
 		CR:          0.9,
 		Strategy:    DERand1Bin,
 		Seed:        42,
+		Workers:     0,
 	}
 }
 
@@ -236,67 +367,107 @@ func DifferentialEvolution(f ObjectiveFunc, dim, population, generations int, bo
 }
 
 func DifferentialEvolutionWithSettings(f ObjectiveFunc, dim int, bounds [][2]float64, settings DESettings) []float64 {
+	return runDE(f, bounds, newDEState(f, dim, bounds, settings), settings)
+}
+
+func newDEState(f ObjectiveFunc, dim int, bounds [][2]float64, settings DESettings) *DEState {
 	rng := NewRNG(settings.Seed)
 	pop := make([][]float64, settings.Population)
-	fitness := make([]float64, settings.Population)
-	bestIdx := 0
 	for i := 0; i < settings.Population; i++ {
 		pop[i] = randomVector(rng, dim, bounds)
-		fitness[i] = f(pop[i])
+	}
+	fitness := evaluatePopulation(f, pop, settings.Workers)
+	bestIdx := 0
+	for i := 1; i < settings.Population; i++ {
 		if fitness[i] < fitness[bestIdx] {
 			bestIdx = i
 		}
 	}
-	for gen := 0; gen < settings.Generations; gen++ {
-		for i := 0; i < settings.Population; i++ {
-			a := rng.Intn(settings.Population)
-			b := rng.Intn(settings.Population)
-			c := rng.Intn(settings.Population)
-			d := rng.Intn(settings.Population)
-			e := rng.Intn(settings.Population)
-			for a == i {
-				a = rng.Intn(settings.Population)
-			}
-			for b == i || b == a {
-				b = rng.Intn(settings.Population)
-			}
-			for c == i || c == a || c == b {
-				c = rng.Intn(settings.Population)
+	return &DEState{Population: pop, Fitness: fitness, BestIdx: bestIdx, RNG: rng.State()}
+}
+
+func runDE(f ObjectiveFunc, bounds [][2]float64, s *DEState, settings DESettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	pop, fitness := s.Population, s.Fitness
+	n := len(pop)
+	trials := make([][]float64, n)
+	trialFits := make([]float64, n)
+	for s.Generation < settings.Generations {
+		if settings.Workers > 1 {
+			rng.Next()
+			bestIdx := s.BestIdx
+			parallelFor(n, settings.Workers, func(i int) {
+				trials[i] = deTrial(rng.Derive(uint64(i)), pop, i, bestIdx, bounds, settings)
+				trialFits[i] = f(trials[i])
+			})
+			for i := 0; i < n; i++ {
+				s.BestIdx = deSelect(pop, fitness, i, trials[i], trialFits[i], s.BestIdx)
 			}
-			for d == i || d == a || d == b || d == c {
-				d = rng.Intn(settings.Population)
-			}
-			for e == i || e == a || e == b || e == c || e == d {
-				e = rng.Intn(settings.Population)
-			}
-			trial := make([]float64, dim)
-			jRand := rng.Intn(dim)
-			for j := 0; j < dim; j++ {
-				if rng.Float64() < settings.CR || j == jRand {
-					switch settings.Strategy {
-					case DERand1Bin:
-						trial[j] = pop[a][j] + settings.F*(pop[b][j]-pop[c][j])
-					case DEBest1Bin:
-						trial[j] = pop[bestIdx][j] + settings.F*(pop[b][j]-pop[c][j])
-					case DERand2Bin:
-						trial[j] = pop[a][j] + settings.F*(pop[b][j]-pop[c][j]+pop[d][j]-pop[e][j])
-					}
-					trial[j] = clampValue(trial[j], bounds[j][0], bounds[j][1])
-				} else {
-					trial[j] = pop[i][j]
-				}
+		} else {
+			for i := 0; i < n; i++ {
+				trial := deTrial(rng, pop, i, s.BestIdx, bounds, settings)
+				s.BestIdx = deSelect(pop, fitness, i, trial, f(trial), s.BestIdx)
 			}
-			trialFit := f(trial)
-			if trialFit < fitness[i] {
-				pop[i] = trial
-				fitness[i] = trialFit
-				if trialFit < fitness[bestIdx] {
-					bestIdx = i
-				}
+		}
+		s.RNG = rng.State()
+		s.Generation++
+		settings.Checkpoint.notify(s.Generation, s)
+	}
+	return pop[s.BestIdx]
+}
+
+func deTrial(rng *RNG, pop [][]float64, i, bestIdx int, bounds [][2]float64, settings DESettings) []float64 {
+	n := len(pop)
+	dim := len(pop[i])
+	a := rng.Intn(n)
+	b := rng.Intn(n)
+	c := rng.Intn(n)
+	d := rng.Intn(n)
+	e := rng.Intn(n)
+	for a == i {
+		a = rng.Intn(n)
+	}
+	for b == i || b == a {
+		b = rng.Intn(n)
+	}
+	for c == i || c == a || c == b {
+		c = rng.Intn(n)
+	}
+	for d == i || d == a || d == b || d == c {
+		d = rng.Intn(n)
+	}
+	for e == i || e == a || e == b || e == c || e == d {
+		e = rng.Intn(n)
+	}
+	trial := make([]float64, dim)
+	jRand := rng.Intn(dim)
+	for j := 0; j < dim; j++ {
+		if rng.Float64() < settings.CR || j == jRand {
+			switch settings.Strategy {
+			case DERand1Bin:
+				trial[j] = pop[a][j] + settings.F*(pop[b][j]-pop[c][j])
+			case DEBest1Bin:
+				trial[j] = pop[bestIdx][j] + settings.F*(pop[b][j]-pop[c][j])
+			case DERand2Bin:
+				trial[j] = pop[a][j] + settings.F*(pop[b][j]-pop[c][j]+pop[d][j]-pop[e][j])
 			}
+			trial[j] = clampValue(trial[j], bounds[j][0], bounds[j][1])
+		} else {
+			trial[j] = pop[i][j]
+		}
+	}
+	return trial
+}
+
+func deSelect(pop [][]float64, fitness []float64, i int, trial []float64, trialFit float64, bestIdx int) int {
+	if trialFit < fitness[i] {
+		pop[i] = trial
+		fitness[i] = trialFit
+		if trialFit < fitness[bestIdx] {
+			bestIdx = i
 		}
 	}
-	return pop[bestIdx]
+	return bestIdx
 }
 
 type GASettings struct {
@@ -307,6 +478,10 @@ type GASettings struct {
 	Tournament    int
 	Elitism       int
 	Seed          uint64
+	// Workers is the number of goroutines evaluating each generation; the
+	// offspring are bred serially, so results do not depend on it.
+	Workers    int
+	Checkpoint CheckpointSettings
 }
 
 func DefaultGASettings(population, generations int) GASettings {
@@ -318,6 +493,7 @@ func DefaultGASettings(population, generations int) GASettings {
 		Tournament:    3,
 		Elitism:       1,
 		Seed:          42,
+		Workers:       0,
 	}
 }
 
@@ -329,13 +505,18 @@ func GeneticAlgorithm(f ObjectiveFunc, dim, popSize, generations int, bounds [][
 func GeneticAlgorithmWithSettings(f ObjectiveFunc, dim int, bounds [][2]float64, settings GASettings) []float64 {
 	rng := NewRNG(settings.Seed)
 	pop := make([][]float64, settings.Population)
-	fitness := make([]float64, settings.Population)
 	for i := range pop {
 		pop[i] = randomVector(rng, dim, bounds)
-		fitness[i] = f(pop[i])
 	}
+	fitness := evaluatePopulation(f, pop, settings.Workers)
+	return runGA(f, bounds, &GAState{Population: pop, Fitness: fitness, RNG: rng.State()}, settings)
+}
+
+func runGA(f ObjectiveFunc, bounds [][2]float64, s *GAState, settings GASettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	pop, fitness := s.Population, s.Fitness
 	bestIdx := 0
-	for gen := 0; gen < settings.Generations; gen++ {
+	for s.Generation < settings.Generations {
 		newPop := make([][]float64, 0, settings.Population)
 		for e := 0; e < settings.Elitism; e++ {
 			bestIdx = argmin(fitness)
@@ -360,10 +541,10 @@ func GeneticAlgorithmWithSettings(f ObjectiveFunc, dim int, bounds [][2]float64,
 			}
 		}
 		pop = newPop
-		fitness = make([]float64, settings.Population)
-		for i := range pop {
-			fitness[i] = f(pop[i])
-		}
+		fitness = evaluatePopulation(f, pop, settings.Workers)
+		s.Population, s.Fitness, s.RNG = pop, fitness, rng.State()
+		s.Generation++
+		settings.Checkpoint.notify(s.Generation, s)
 	}
 	best := 0
 	for i := 1; i < len(pop); i++ {
@@ -420,6 +601,7 @@ type AnnealSettings struct {
 	Iterations  int
 	StepScale   float64
 	Seed        uint64
+	Checkpoint  CheckpointSettings
 }
 
 func DefaultAnnealSettings() AnnealSettings {
@@ -442,29 +624,41 @@ func SimulatedAnnealing(f ObjectiveFunc, x0 []float64, temp, cooling float64, it
 }
 
 func SimulatedAnnealingWithSettings(f ObjectiveFunc, x0 []float64, bounds [][2]float64, settings AnnealSettings) []float64 {
-	rng := NewRNG(settings.Seed)
 	x := clampVector(x0, bounds)
-	best := cloneVector(x)
-	bestVal := f(best)
-	currentVal := bestVal
-	temp := settings.InitialTemp
-	for temp > settings.MinTemp {
+	val := f(x)
+	state := &AnnealState{
+		X:            x,
+		CurrentValue: val,
+		Best:         cloneVector(x),
+		BestValue:    val,
+		Temperature:  settings.InitialTemp,
+		RNG:          NewRNG(settings.Seed).State(),
+	}
+	return runAnnealing(f, bounds, state, settings)
+}
+
+func runAnnealing(f ObjectiveFunc, bounds [][2]float64, s *AnnealState, settings AnnealSettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	for s.Temperature > settings.MinTemp {
 		for i := 0; i < settings.Iterations; i++ {
-			cand := neighborVector(rng, x, bounds, settings.StepScale)
+			cand := neighborVector(rng, s.X, bounds, settings.StepScale)
 			val := f(cand)
-			delta := val - currentVal
-			if delta < 0 || rng.Float64() < math.Exp(-delta/temp) {
-				x = cand
-				currentVal = val
-				if val < bestVal {
-					bestVal = val
-					best = cloneVector(cand)
+			delta := val - s.CurrentValue
+			if delta < 0 || rng.Float64() < math.Exp(-delta/s.Temperature) {
+				s.X = cand
+				s.CurrentValue = val
+				if val < s.BestValue {
+					s.BestValue = val
+					s.Best = cloneVector(cand)
 				}
 			}
 		}
-		temp *= settings.Alpha
+		s.Temperature *= settings.Alpha
+		s.Level++
+		s.RNG = rng.State()
+		settings.Checkpoint.notify(s.Level, s)
 	}
-	return best
+	return s.Best
 }
 
 func neighborVector(rng *RNG, x []float64, bounds [][2]float64, step float64) []float64 {
@@ -482,11 +676,14 @@ func neighborVector(rng *RNG, x []float64, bounds [][2]float64, step float64) []
 	return out
 }
 
+const tabuCandidates = 8
+
 type TabuSettings struct {
 	Iterations int
 	TabuSize   int
 	StepScale  float64
 	Seed       uint64
+	Checkpoint CheckpointSettings
 }
 
 func DefaultTabuSettings() TabuSettings {
@@ -506,15 +703,23 @@ func TabuSearch(f ObjectiveFunc, x0 []float64, tabuSize, iterations int) []float
 }
 
 func TabuSearchWithSettings(f ObjectiveFunc, x0 []float64, bounds [][2]float64, settings TabuSettings) []float64 {
-	rng := NewRNG(settings.Seed)
 	x := clampVector(x0, bounds)
-	best := cloneVector(x)
-	bestVal := f(best)
-	mem := make([][]float64, 0, settings.TabuSize)
-	for iter := 0; iter < settings.Iterations; iter++ {
-		candidates := make([][]float64, 0, 8)
-		for i := 0; i < 8; i++ {
-			cand := neighborVector(rng, x, bounds, settings.StepScale)
+	state := &TabuState{
+		X:         x,
+		Best:      cloneVector(x),
+		BestValue: f(x),
+		Memory:    make([][]float64, 0, settings.TabuSize),
+		RNG:       NewRNG(settings.Seed).State(),
+	}
+	return runTabu(f, bounds, state, settings)
+}
+
+func runTabu(f ObjectiveFunc, bounds [][2]float64, s *TabuState, settings TabuSettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	for s.Iteration < settings.Iterations {
+		candidates := make([][]float64, 0, tabuCandidates)
+		for i := 0; i < tabuCandidates; i++ {
+			cand := neighborVector(rng, s.X, bounds, settings.StepScale)
 			candidates = append(candidates, cand)
 		}
 		selected := candidates[0]
@@ -526,19 +731,22 @@ func TabuSearchWithSettings(f ObjectiveFunc, x0 []float64, bounds [][2]float64,
 				selected = candidates[i]
 			}
 		}
-		if !isTabu(selected, mem) {
-			x = selected
-			if selectedVal < bestVal {
-				bestVal = selectedVal
-				best = cloneVector(selected)
+		if !isTabu(selected, s.Memory) {
+			s.X = selected
+			if selectedVal < s.BestValue {
+				s.BestValue = selectedVal
+				s.Best = cloneVector(selected)
 			}
-			mem = append(mem, cloneVector(selected))
-			if len(mem) > settings.TabuSize {
-				mem = mem[1:]
+			s.Memory = append(s.Memory, cloneVector(selected))
+			if len(s.Memory) > settings.TabuSize {
+				s.Memory = s.Memory[1:]
 			}
 		}
+		s.RNG = rng.State()
+		s.Iteration++
+		settings.Checkpoint.notify(s.Iteration, s)
 	}
-	return best
+	return s.Best
 }
 
 func isTabu(x []float64, tabu [][]float64) bool {
@@ -558,6 +766,10 @@ type HarmonySettings struct {
 	Bandwidth    float64
 	Seed         uint64
 	ImproveLimit float64
+	// Batch > 1 improvises that many harmonies per iteration from the same
+	// memory, evaluated by Workers goroutines.
+	Batch   int
+	Workers int
 }
 
 func DefaultHarmonySettings() HarmonySettings {
@@ -569,45 +781,53 @@ func DefaultHarmonySettings() HarmonySettings {
 		Bandwidth:    0.01,
 		Seed:         42,
 		ImproveLimit: 1e-12,
+		Batch:        1,
+		Workers:      0,
 	}
 }
 
 func HarmonySearch(f ObjectiveFunc, dim int, bounds [][2]float64, settings HarmonySettings) []float64 {
 	rng := NewRNG(settings.Seed)
 	memory := make([][]float64, settings.MemorySize)
-	values := make([]float64, settings.MemorySize)
-	best := 0
 	for i := 0; i < settings.MemorySize; i++ {
 		memory[i] = randomVector(rng, dim, bounds)
-		values[i] = f(memory[i])
+	}
+	values := evaluatePopulation(f, memory, settings.Workers)
+	best := 0
+	for i := 1; i < settings.MemorySize; i++ {
 		if values[i] < values[best] {
 			best = i
 		}
 	}
 	bestVal := values[best]
 	stall := 0
+	batch := settings.Batch
+	if batch < 1 {
+		batch = 1
+	}
+	harmonies := make([][]float64, batch)
+	harmonyVals := make([]float64, batch)
 	for iter := 0; iter < settings.Iterations; iter++ {
-		newHarmony := make([]float64, dim)
-		for j := 0; j < dim; j++ {
-			if rng.Float64() < settings.HMCR {
-				idx := rng.Intn(settings.MemorySize)
-				newHarmony[j] = memory[idx][j]
-				if rng.Float64() < settings.PAR {
-					newHarmony[j] += (rng.Float64()*2 - 1) * settings.Bandwidth
+		if batch == 1 {
+			harmonies[0] = improviseHarmony(rng, memory, dim, bounds, settings)
+			harmonyVals[0] = f(harmonies[0])
+		} else {
+			rng.Next()
+			parallelFor(batch, settings.Workers, func(i int) {
+				harmonies[i] = improviseHarmony(rng.Derive(uint64(i)), memory, dim, bounds, settings)
+				harmonyVals[i] = f(harmonies[i])
+			})
+		}
+		for i := 0; i < batch; i++ {
+			val := harmonyVals[i]
+			worst := argmax(values)
+			if val < values[worst] {
+				memory[worst] = harmonies[i]
+				values[worst] = val
+				if val < bestVal {
+					bestVal = val
+					best = worst
 				}
-			} else {
-				newHarmony[j] = rng.Range(bounds[j][0], bounds[j][1])
-			}
-			newHarmony[j] = clampValue(newHarmony[j], bounds[j][0], bounds[j][1])
-		}
-		val := f(newHarmony)
-		worst := argmax(values)
-		if val < values[worst] {
-			memory[worst] = newHarmony
-			values[worst] = val
-			if val < bestVal {
-				bestVal = val
-				best = worst
 			}
 		}
 		if absO(bestVal-values[best]) < settings.ImproveLimit {
@@ -622,3 +842,20 @@ func HarmonySearch(f ObjectiveFunc, dim int, bounds [][2]float64, settings Harmo
 	}
 	return memory[best]
 }
+
+func improviseHarmony(rng *RNG, memory [][]float64, dim int, bounds [][2]float64, settings HarmonySettings) []float64 {
+	harmony := make([]float64, dim)
+	for j := 0; j < dim; j++ {
+		if rng.Float64() < settings.HMCR {
+			idx := rng.Intn(len(memory))
+			harmony[j] = memory[idx][j]
+			if rng.Float64() < settings.PAR {
+				harmony[j] += (rng.Float64()*2 - 1) * settings.Bandwidth
+			}
+		} else {
+			harmony[j] = rng.Range(bounds[j][0], bounds[j][1])
+		}
+		harmony[j] = clampValue(harmony[j], bounds[j][0], bounds[j][1])
+	}
+	return harmony
+}
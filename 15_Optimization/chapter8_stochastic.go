@@ -134,6 +134,8 @@ type CEMSettings struct {
 	InitStd    float64
 	MinStd     float64
 	Bounds     [][2]float64
+	Workers    int
+	Checkpoint CheckpointSettings
 }
 
 func DefaultCEMSettings(samples int, bounds [][2]float64) CEMSettings {
@@ -145,32 +147,44 @@ func DefaultCEMSettings(samples int, bounds [][2]float64) CEMSettings {
 		InitStd:    1.0,
 		MinStd:     1e-4,
 		Bounds:     bounds,
+		Workers:    0,
 	}
 }
 
 func CrossEntropyMethod(f ObjectiveFunc, mean []float64, settings CEMSettings) []float64 {
-	rng := NewRNG(settings.Seed)
-	dim := len(mean)
-	std := make([]float64, dim)
+	std := make([]float64, len(mean))
 	for i := range std {
 		std[i] = settings.InitStd
 	}
 	best := stochClone(mean)
-	bestVal := f(best)
-	for iter := 0; iter < settings.Iterations; iter++ {
+	state := &CEMState{
+		Mean:      mean,
+		Std:       std,
+		Best:      best,
+		BestValue: f(best),
+		RNG:       NewRNG(settings.Seed).State(),
+	}
+	return runCEM(f, state, settings)
+}
+
+func runCEM(f ObjectiveFunc, s *CEMState, settings CEMSettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	dim := len(s.Mean)
+	for s.Iteration < settings.Iterations {
+		mean, std := s.Mean, s.Std
 		samples := make([][]float64, settings.Samples)
-		values := make([]float64, settings.Samples)
 		for i := 0; i < settings.Samples; i++ {
 			cand := make([]float64, dim)
 			for j := 0; j < dim; j++ {
 				cand[j] = mean[j] + std[j]*normalSample(rng)
 			}
-			cand = stochClamp(cand, settings.Bounds)
-			samples[i] = cand
-			values[i] = f(cand)
-			if values[i] < bestVal {
-				bestVal = values[i]
-				best = stochClone(cand)
+			samples[i] = stochClamp(cand, settings.Bounds)
+		}
+		values := evaluatePopulation(f, samples, settings.Workers)
+		for i := 0; i < settings.Samples; i++ {
+			if values[i] < s.BestValue {
+				s.BestValue = values[i]
+				s.Best = stochClone(samples[i])
 			}
 		}
 		eliteCount := int(math.Max(1, math.Round(float64(settings.Samples)*settings.EliteRatio)))
@@ -186,8 +200,11 @@ func CrossEntropyMethod(f ObjectiveFunc, mean []float64, settings CEMSettings) [
 				std[j] = settings.MinStd
 			}
 		}
+		s.Mean, s.Std, s.RNG = mean, std, rng.State()
+		s.Iteration++
+		settings.Checkpoint.notify(s.Iteration, s)
 	}
-	return best
+	return s.Best
 }
 
 func argsort(values []float64) []int {
@@ -222,6 +239,7 @@ type CMAESSettings struct {
 	Seed       uint64
 	Sigma      float64
 	Bounds     [][2]float64
+	Checkpoint CheckpointSettings
 }
 
 func DefaultCMAESSettings(population int, bounds [][2]float64) CMAESSettings {
@@ -235,8 +253,25 @@ func DefaultCMAESSettings(population int, bounds [][2]float64) CMAESSettings {
 }
 
 func CMAESDiagonal(f ObjectiveFunc, mean []float64, settings CMAESSettings) []float64 {
-	rng := NewRNG(settings.Seed)
-	dim := len(mean)
+	diag := make([]float64, len(mean))
+	for i := range diag {
+		diag[i] = 1
+	}
+	best := stochClone(mean)
+	state := &CMAESDiagonalState{
+		Mean:      mean,
+		Diag:      diag,
+		Sigma:     settings.Sigma,
+		Best:      best,
+		BestValue: f(best),
+		RNG:       NewRNG(settings.Seed).State(),
+	}
+	return runCMAESDiagonal(f, state, settings)
+}
+
+func runCMAESDiagonal(f ObjectiveFunc, s *CMAESDiagonalState, settings CMAESSettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	dim := len(s.Mean)
 	weights := make([]float64, settings.Population)
 	for i := 0; i < settings.Population; i++ {
 		weights[i] = math.Log(float64(settings.Population)+0.5) - math.Log(float64(i)+1)
@@ -251,14 +286,8 @@ func CMAESDiagonal(f ObjectiveFunc, mean []float64, settings CMAESSettings) []fl
 	for i := range weights {
 		weights[i] /= wsum
 	}
-	sigma := settings.Sigma
-	diag := make([]float64, dim)
-	for i := range diag {
-		diag[i] = 1
-	}
-	best := stochClone(mean)
-	bestVal := f(best)
-	for iter := 0; iter < settings.Iterations; iter++ {
+	mean, diag, sigma := s.Mean, s.Diag, s.Sigma
+	for s.Iteration < settings.Iterations {
 		pop := make([][]float64, settings.Population)
 		vals := make([]float64, settings.Population)
 		for i := 0; i < settings.Population; i++ {
@@ -269,9 +298,9 @@ func CMAESDiagonal(f ObjectiveFunc, mean []float64, settings CMAESSettings) []fl
 			cand = stochClamp(cand, settings.Bounds)
 			pop[i] = cand
 			vals[i] = f(cand)
-			if vals[i] < bestVal {
-				bestVal = vals[i]
-				best = stochClone(cand)
+			if vals[i] < s.BestValue {
+				s.BestValue = vals[i]
+				s.Best = stochClone(cand)
 			}
 		}
 		order := argsort(vals)
@@ -299,8 +328,11 @@ func CMAESDiagonal(f ObjectiveFunc, mean []float64, settings CMAESSettings) []fl
 		if sigma < 1e-4 {
 			sigma = 1e-4
 		}
+		s.Mean, s.Diag, s.Sigma, s.RNG = mean, diag, sigma, rng.State()
+		s.Iteration++
+		settings.Checkpoint.notify(s.Iteration, s)
 	}
-	return best
+	return s.Best
 }
 
 type SPSASettings struct {
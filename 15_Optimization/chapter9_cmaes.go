@@ -0,0 +1,380 @@
+// 2026 Update: Covariance Matrix Adaptation
+package optimization
+
+import "math"
+
+type CMAESRestart int
+
+const (
+	CMAESNoRestart CMAESRestart = iota
+	CMAESIPOP
+	CMAESBIPOP
+)
+
+type CMAESFullSettings struct {
+	Population     int
+	Iterations     int
+	MaxEvaluations int
+	Seed           uint64
+	Sigma          float64
+	Bounds         [][2]float64
+	Restart        CMAESRestart
+	MaxRestarts    int
+	PopGrowth      float64
+	TolFun         float64
+	TolX           float64
+	Target         float64
+	Workers        int
+	Checkpoint     CheckpointSettings
+}
+
+func DefaultCMAESFullSettings(bounds [][2]float64) CMAESFullSettings {
+	return CMAESFullSettings{
+		Population:     0,
+		Iterations:     1000,
+		MaxEvaluations: 100000,
+		Seed:           42,
+		Sigma:          0.5,
+		Bounds:         bounds,
+		Restart:        CMAESNoRestart,
+		MaxRestarts:    9,
+		PopGrowth:      2,
+		TolFun:         1e-12,
+		TolX:           1e-12,
+		Target:         math.Inf(-1),
+		Workers:        0,
+	}
+}
+
+func CMAESFull(f ObjectiveFunc, mean []float64, settings CMAESFullSettings) []float64 {
+	return runCMAESFull(f, newCMAESFullState(mean, settings), settings)
+}
+
+func newCMAESFullState(mean []float64, settings CMAESFullSettings) *CMAESFullState {
+	basePop, _, budget := cmaesFullLimits(len(mean), settings)
+	s := &CMAESFullState{
+		Start:     stochClone(mean),
+		Best:      stochClone(mean),
+		BestValue: math.Inf(1),
+		LargePop:  basePop,
+		Large:     true,
+		RNG:       NewRNG(settings.Seed).State(),
+	}
+	s.beginRun(stochClone(mean), settings.Sigma, basePop, budget)
+	return s
+}
+
+func cmaesFullLimits(dim int, settings CMAESFullSettings) (int, float64, int) {
+	basePop := settings.Population
+	if basePop <= 0 {
+		basePop = 4 + int(3*math.Log(float64(dim)))
+	}
+	growth := settings.PopGrowth
+	if growth <= 1 {
+		growth = 2
+	}
+	budget := settings.MaxEvaluations
+	if budget <= 0 {
+		budget = math.MaxInt
+	}
+	return basePop, growth, budget
+}
+
+// beginRun resets the search distribution for a fresh run of lambda samples
+// per generation and at most budget evaluations.
+func (s *CMAESFullState) beginRun(mean []float64, sigma float64, lambda, budget int) {
+	n := len(mean)
+	s.Mean, s.Sigma, s.Lambda, s.RunBudget = mean, sigma, lambda, budget
+	s.C, s.B = identityMat(n), identityMat(n)
+	s.D = make([]float64, n)
+	for i := range s.D {
+		s.D[i] = 1
+	}
+	s.PC, s.PS = make([]float64, n), make([]float64, n)
+	s.RunBest, s.RunBestValue = stochClone(mean), math.Inf(1)
+	s.History = nil
+	s.Generation, s.Evaluations, s.EigenEval = 0, 0, 0
+	s.RunDone = false
+}
+
+func runCMAESFull(f ObjectiveFunc, s *CMAESFullState, settings CMAESFullSettings) []float64 {
+	rng := &RNG{state: s.RNG}
+	basePop, growth, budget := cmaesFullLimits(len(s.Start), settings)
+	for {
+		cmaesGenerations(f, rng, s, settings)
+		s.Used += s.Evaluations
+		if s.Large {
+			s.LargeEvals += s.Evaluations
+		} else {
+			s.SmallEvals += s.Evaluations
+		}
+		if s.RunBestValue < s.BestValue {
+			s.Best, s.BestValue = s.RunBest, s.RunBestValue
+		}
+		if settings.Restart == CMAESNoRestart || s.Restarts >= settings.MaxRestarts || s.Used >= budget || s.BestValue <= settings.Target {
+			return s.Best
+		}
+		start := cmaesRestartPoint(rng, s.Start, settings.Bounds)
+		pop := s.LargePop
+		sigma := settings.Sigma
+		s.Large = true
+		if settings.Restart == CMAESBIPOP && s.Restarts > 0 && s.SmallEvals < s.LargeEvals {
+			u := rng.Float64()
+			pop = int(float64(basePop) * math.Pow(0.5*float64(s.LargePop)/float64(basePop), u*u))
+			if pop < basePop {
+				pop = basePop
+			}
+			sigma = settings.Sigma * math.Pow(10, -2*rng.Float64())
+			s.Large = false
+		} else {
+			s.LargePop = int(math.Ceil(float64(s.LargePop) * growth))
+			pop = s.LargePop
+		}
+		s.Restarts++
+		s.beginRun(start, sigma, pop, budget-s.Used)
+	}
+}
+
+func cmaesRestartPoint(rng *RNG, mean []float64, bounds [][2]float64) []float64 {
+	if bounds == nil || len(bounds) != len(mean) {
+		return stochClone(mean)
+	}
+	return stochUniform(rng, len(mean), bounds)
+}
+
+// cmaesGenerations advances the current run until it stops, notifying the
+// checkpoint handler after every generation.
+func cmaesGenerations(f ObjectiveFunc, rng *RNG, s *CMAESFullState, settings CMAESFullSettings) {
+	n := len(s.Mean)
+	nf := float64(n)
+	lambda := s.Lambda
+	mu := lambda / 2
+	if mu < 1 {
+		mu = 1
+	}
+	weights := make([]float64, mu)
+	wsum := 0.0
+	for i := range weights {
+		weights[i] = math.Log(float64(mu)+0.5) - math.Log(float64(i)+1)
+		wsum += weights[i]
+	}
+	w2 := 0.0
+	for i := range weights {
+		weights[i] /= wsum
+		w2 += weights[i] * weights[i]
+	}
+	mueff := 1 / w2
+	cc := (4 + mueff/nf) / (nf + 4 + 2*mueff/nf)
+	cs := (mueff + 2) / (nf + mueff + 5)
+	c1 := 2 / ((nf+1.3)*(nf+1.3) + mueff)
+	cmu := math.Min(1-c1, 2*(mueff-2+1/mueff)/((nf+2)*(nf+2)+mueff))
+	damps := 1 + 2*math.Max(0, math.Sqrt((mueff-1)/(nf+1))-1) + cs
+	chiN := math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+	historyLen := 10 + int(30*nf/float64(lambda))
+
+	C, B, D, pc, ps := s.C, s.B, s.D, s.PC, s.PS
+	for !s.RunDone && s.Generation < settings.Iterations && s.Evaluations+lambda <= s.RunBudget {
+		mean, sigma := s.Mean, s.Sigma
+		zs := make([][]float64, lambda)
+		ys := make([][]float64, lambda)
+		xs := make([][]float64, lambda)
+		repaired := make([][]float64, lambda)
+		for k := 0; k < lambda; k++ {
+			z := make([]float64, n)
+			for i := range z {
+				z[i] = normalSample(rng)
+			}
+			y := make([]float64, n)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					y[i] += B[i][j] * D[j] * z[j]
+				}
+			}
+			x := make([]float64, n)
+			for i := range x {
+				x[i] = mean[i] + sigma*y[i]
+			}
+			zs[k], ys[k], xs[k] = z, y, x
+			repaired[k] = stochClamp(x, settings.Bounds)
+		}
+		raw := evaluatePopulation(f, repaired, settings.Workers)
+		s.Evaluations += lambda
+		fitness := make([]float64, lambda)
+		for k := 0; k < lambda; k++ {
+			fitness[k] = raw[k] + cmaesBoundPenalty(xs[k], repaired[k], raw[k], settings.Bounds)
+			if raw[k] < s.RunBestValue {
+				s.RunBestValue = raw[k]
+				s.RunBest = stochClone(repaired[k])
+			}
+		}
+		order := argsort(fitness)
+		mean = make([]float64, n)
+		step := make([]float64, n)
+		for r := 0; r < mu; r++ {
+			idx := order[r]
+			for i := 0; i < n; i++ {
+				mean[i] += weights[r] * xs[idx][i]
+				step[i] += weights[r] * ys[idx][i]
+			}
+		}
+		zw := make([]float64, n)
+		for r := 0; r < mu; r++ {
+			for i := 0; i < n; i++ {
+				zw[i] += weights[r] * zs[order[r]][i]
+			}
+		}
+		bz := matVec(B, zw)
+		csFactor := math.Sqrt(cs * (2 - cs) * mueff)
+		for i := 0; i < n; i++ {
+			ps[i] = (1-cs)*ps[i] + csFactor*bz[i]
+		}
+		psNorm := vecNorm(ps)
+		hsig := 0.0
+		if psNorm/math.Sqrt(1-math.Pow(1-cs, 2*float64(s.Generation+1)))/chiN < 1.4+2/(nf+1) {
+			hsig = 1
+		}
+		ccFactor := math.Sqrt(cc * (2 - cc) * mueff)
+		for i := 0; i < n; i++ {
+			pc[i] = (1-cc)*pc[i] + hsig*ccFactor*step[i]
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j <= i; j++ {
+				rankMu := 0.0
+				for r := 0; r < mu; r++ {
+					y := ys[order[r]]
+					rankMu += weights[r] * y[i] * y[j]
+				}
+				v := (1-c1-cmu)*C[i][j] + c1*(pc[i]*pc[j]+(1-hsig)*cc*(2-cc)*C[i][j]) + cmu*rankMu
+				C[i][j] = v
+				C[j][i] = v
+			}
+		}
+		sigma *= math.Exp((cs / damps) * (psNorm/chiN - 1))
+		if float64(s.Evaluations-s.EigenEval) > float64(lambda)/(c1+cmu)/nf/10 {
+			s.EigenEval = s.Evaluations
+			vals, vecs := jacobiEigen(C, 60)
+			for i := range vals {
+				if vals[i] < 1e-20 {
+					vals[i] = 1e-20
+				}
+				D[i] = math.Sqrt(vals[i])
+			}
+			B = vecs
+		}
+		s.Mean, s.Sigma, s.B = mean, sigma, B
+		if s.RunBestValue <= settings.Target {
+			s.RunDone = true
+		} else {
+			s.History = append(s.History, fitness[order[0]])
+			if len(s.History) > historyLen {
+				s.History = append(s.History[:0], s.History[1:]...)
+			}
+			s.RunDone = len(s.History) == historyLen && cmaesRange(s.History) < settings.TolFun && cmaesRange(fitness) < settings.TolFun ||
+				cmaesConverged(sigma, C, pc, settings.TolX) || cmaesIllConditioned(D)
+		}
+		s.Generation++
+		s.Iteration++
+		s.RNG = rng.State()
+		settings.Checkpoint.notify(s.Iteration, s)
+	}
+}
+
+func cmaesBoundPenalty(x, repaired []float64, value float64, bounds [][2]float64) float64 {
+	if bounds == nil || len(bounds) != len(x) {
+		return 0
+	}
+	dist := 0.0
+	for i := range x {
+		span := absO(bounds[i][1] - bounds[i][0])
+		if span == 0 {
+			span = 1
+		}
+		d := (x[i] - repaired[i]) / span
+		dist += d * d
+	}
+	return (1 + absO(value)) * dist
+}
+
+func cmaesRange(values []float64) float64 {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	return high - low
+}
+
+func cmaesConverged(sigma float64, C [][]float64, pc []float64, tolX float64) bool {
+	for i := range C {
+		if sigma*math.Max(absO(pc[i]), math.Sqrt(C[i][i])) > tolX {
+			return false
+		}
+	}
+	return true
+}
+
+func cmaesIllConditioned(D []float64) bool {
+	low, high := math.Inf(1), 0.0
+	for _, d := range D {
+		low = math.Min(low, d)
+		high = math.Max(high, d)
+	}
+	return high > 1e7*low
+}
+
+func jacobiEigen(A [][]float64, sweeps int) ([]float64, [][]float64) {
+	n := len(A)
+	a := make([][]float64, n)
+	for i := range A {
+		a[i] = cloneVector(A[i])
+	}
+	v := identityMat(n)
+	for sweep := 0; sweep < sweeps; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if absO(a[p][q]) < 1e-300 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (absO(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp := a[k][p]
+					akq := a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk := a[p][k]
+					aqk := a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp := v[k][p]
+					vkq := v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	vals := make([]float64, n)
+	for i := range vals {
+		vals[i] = a[i][i]
+	}
+	return vals, v
+}
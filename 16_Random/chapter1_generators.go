@@ -0,0 +1,180 @@
+// 2026 Update: Pseudorandom Generators
+package random
+
+import (
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Every generator here implements math/rand/v2.Source, so it can drive
+// rand.New as well as the samplers in this package and the RNG types of the
+// other modules.
+var (
+	_ rand.Source = (*PCG64)(nil)
+	_ rand.Source = (*Xoshiro256)(nil)
+)
+
+// PCG64 is the 128-bit PCG generator with the DXSM output function. It
+// produces the same stream as math/rand/v2.NewPCG for the same seeds, and
+// adds jump-ahead for parallel streams.
+type PCG64 struct {
+	hi, lo uint64
+}
+
+const (
+	pcgMulHi = 2549297995355413924
+	pcgMulLo = 4865540595714422341
+	pcgIncHi = 6364136223846793005
+	pcgIncLo = 1442695040888963407
+)
+
+func NewPCG64(seed1, seed2 uint64) *PCG64 {
+	return &PCG64{hi: seed1, lo: seed2}
+}
+
+func (p *PCG64) Uint64() uint64 {
+	p.hi, p.lo = mul128(p.hi, p.lo, pcgMulHi, pcgMulLo)
+	p.hi, p.lo = add128(p.hi, p.lo, pcgIncHi, pcgIncLo)
+	hi := p.hi
+	hi ^= hi >> 32
+	hi *= 0xda942042e4dd58b5
+	hi ^= hi >> 48
+	return hi * (p.lo | 1)
+}
+
+func (p *PCG64) Float64() float64 {
+	return float64(p.Uint64()>>11) * 0x1p-53
+}
+
+// Advance moves the generator deltaHi·2^64 + deltaLo steps ahead in
+// O(log delta) time (Brown, 1994).
+func (p *PCG64) Advance(deltaHi, deltaLo uint64) {
+	accMulHi, accMulLo := uint64(0), uint64(1)
+	accPlusHi, accPlusLo := uint64(0), uint64(0)
+	curMulHi, curMulLo := uint64(pcgMulHi), uint64(pcgMulLo)
+	curPlusHi, curPlusLo := uint64(pcgIncHi), uint64(pcgIncLo)
+	for deltaHi != 0 || deltaLo != 0 {
+		if deltaLo&1 == 1 {
+			accMulHi, accMulLo = mul128(accMulHi, accMulLo, curMulHi, curMulLo)
+			accPlusHi, accPlusLo = mul128(accPlusHi, accPlusLo, curMulHi, curMulLo)
+			accPlusHi, accPlusLo = add128(accPlusHi, accPlusLo, curPlusHi, curPlusLo)
+		}
+		mHi, mLo := add128(curMulHi, curMulLo, 0, 1)
+		curPlusHi, curPlusLo = mul128(mHi, mLo, curPlusHi, curPlusLo)
+		curMulHi, curMulLo = mul128(curMulHi, curMulLo, curMulHi, curMulLo)
+		deltaLo = deltaLo>>1 | deltaHi<<63
+		deltaHi >>= 1
+	}
+	p.hi, p.lo = mul128(accMulHi, accMulLo, p.hi, p.lo)
+	p.hi, p.lo = add128(p.hi, p.lo, accPlusHi, accPlusLo)
+}
+
+// Jump advances the generator 2^64 steps, splitting the 2^128 period into
+// 2^64 non-overlapping streams.
+func (p *PCG64) Jump() {
+	p.Advance(1, 0)
+}
+
+// Split returns a generator for the current stream and jumps p to the next,
+// so repeated calls hand out non-overlapping streams of 2^64 values.
+func (p *PCG64) Split() *PCG64 {
+	child := *p
+	p.Jump()
+	return &child
+}
+
+func mul128(aHi, aLo, bHi, bLo uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(aLo, bLo)
+	return hi + aHi*bLo + aLo*bHi, lo
+}
+
+func add128(aHi, aLo, bHi, bLo uint64) (uint64, uint64) {
+	lo, carry := bits.Add64(aLo, bLo, 0)
+	hi, _ := bits.Add64(aHi, bHi, carry)
+	return hi, lo
+}
+
+// Xoshiro256 is xoshiro256** (Blackman & Vigna, 2018), with a period of
+// 2^256 - 1 and jump polynomials for parallel streams.
+type Xoshiro256 struct {
+	s [4]uint64
+}
+
+// NewXoshiro256 expands seed into the state with SplitMix64, as the authors
+// recommend, so nearby seeds give unrelated streams.
+func NewXoshiro256(seed uint64) *Xoshiro256 {
+	x := &Xoshiro256{}
+	for i := range x.s {
+		seed += 0x9E3779B97F4A7C15
+		x.s[i] = SplitMix64(seed)
+	}
+	return x
+}
+
+// NewXoshiro256State uses state directly; it must not be all zero.
+func NewXoshiro256State(state [4]uint64) *Xoshiro256 {
+	return &Xoshiro256{s: state}
+}
+
+func (x *Xoshiro256) State() [4]uint64 {
+	return x.s
+}
+
+func (x *Xoshiro256) Uint64() uint64 {
+	s := &x.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+func (x *Xoshiro256) Float64() float64 {
+	return float64(x.Uint64()>>11) * 0x1p-53
+}
+
+// Jump advances the generator 2^128 steps.
+func (x *Xoshiro256) Jump() {
+	x.jump([4]uint64{0x180ec6d33cfd0aba, 0xd5a61266f0c9392c, 0xa9582618e03fc9aa, 0x39abdc4529b1661c})
+}
+
+// LongJump advances the generator 2^192 steps, for distributing streams
+// across machines that each Jump locally.
+func (x *Xoshiro256) LongJump() {
+	x.jump([4]uint64{0x76e15d3efefdcbbf, 0xc5004e441c522fb3, 0x77710069854ee241, 0x39109bb02acbe635})
+}
+
+func (x *Xoshiro256) jump(poly [4]uint64) {
+	var acc [4]uint64
+	for _, word := range poly {
+		for b := 0; b < 64; b++ {
+			if word&(1<<b) != 0 {
+				for i := range acc {
+					acc[i] ^= x.s[i]
+				}
+			}
+			x.Uint64()
+		}
+	}
+	x.s = acc
+}
+
+// Split returns a generator for the current stream and jumps x to the next,
+// so repeated calls hand out non-overlapping streams of 2^128 values.
+func (x *Xoshiro256) Split() *Xoshiro256 {
+	child := *x
+	x.Jump()
+	return &child
+}
+
+// SplitMix64 is the finalizer of Steele, Lea and Flood's SplitMix generator,
+// used here for seeding.
+func SplitMix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
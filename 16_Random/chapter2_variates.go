@@ -0,0 +1,243 @@
+// 2026 Update: Random Variates
+package random
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Rand draws variates from any math/rand/v2.Source, including the generators
+// in this package, and is itself a Source.
+type Rand struct {
+	src rand.Source
+}
+
+func New(src rand.Source) *Rand {
+	return &Rand{src: src}
+}
+
+func (r *Rand) Uint64() uint64 {
+	return r.src.Uint64()
+}
+
+// Float64 returns a uniform value in [0, 1) with 53 random bits.
+func (r *Rand) Float64() float64 {
+	return float64(r.src.Uint64()>>11) * 0x1p-53
+}
+
+// IntN returns a uniform integer in [0, n) without modulo bias (Lemire, 2019).
+func (r *Rand) IntN(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	bound := uint64(n)
+	hi, lo := bits.Mul64(r.src.Uint64(), bound)
+	if lo < bound {
+		threshold := -bound % bound
+		for lo < threshold {
+			hi, lo = bits.Mul64(r.src.Uint64(), bound)
+		}
+	}
+	return int(hi)
+}
+
+// Ziggurat tables (Marsaglia & Tsang, 2000): x[i] are the layer edges, with
+// x[0] the width of the base strip whose overflow is the tail beyond x[1].
+type ziggurat struct {
+	x     []float64
+	f     []float64
+	ratio []float64
+	r     float64
+}
+
+func newZiggurat(layers int, r, v float64, density func(float64) float64, next func(x, v float64) float64) ziggurat {
+	z := ziggurat{x: make([]float64, layers+1), f: make([]float64, layers+1), ratio: make([]float64, layers), r: r}
+	z.x[0] = v / density(r)
+	z.x[1] = r
+	for i := 2; i < layers; i++ {
+		z.x[i] = next(z.x[i-1], v)
+	}
+	for i := range z.x {
+		z.f[i] = density(z.x[i])
+	}
+	for i := range z.ratio {
+		z.ratio[i] = z.x[i+1] / z.x[i]
+	}
+	return z
+}
+
+var (
+	normalZiggurat = newZiggurat(128, 3.442619855899, 9.91256303526217e-3,
+		func(x float64) float64 { return math.Exp(-0.5 * x * x) },
+		func(x, v float64) float64 { return math.Sqrt(-2 * math.Log(v/x+math.Exp(-0.5*x*x))) })
+	exponentialZiggurat = newZiggurat(256, 7.69711747013104972, 3.949659822581572e-3,
+		func(x float64) float64 { return math.Exp(-x) },
+		func(x, v float64) float64 { return -math.Log(v/x + math.Exp(-x)) })
+)
+
+// Normal returns a standard normal variate by the ziggurat method.
+func (r *Rand) Normal() float64 {
+	z := &normalZiggurat
+	for {
+		w := r.src.Uint64()
+		i := int(w & 127)
+		u := float64(w>>11)*0x1p-52 - 1
+		if math.Abs(u) < z.ratio[i] {
+			return u * z.x[i]
+		}
+		if i == 0 {
+			for {
+				x := -math.Log(1-r.Float64()) / z.r
+				y := -math.Log(1 - r.Float64())
+				if 2*y > x*x {
+					return math.Copysign(z.r+x, u)
+				}
+			}
+		}
+		x := u * z.x[i]
+		if z.f[i]+r.Float64()*(z.f[i+1]-z.f[i]) < math.Exp(-0.5*x*x) {
+			return x
+		}
+	}
+}
+
+// Exponential returns an exponential variate with rate 1 by the ziggurat
+// method.
+func (r *Rand) Exponential() float64 {
+	z := &exponentialZiggurat
+	for {
+		w := r.src.Uint64()
+		i := int(w & 255)
+		u := float64(w>>11) * 0x1p-53
+		if u < z.ratio[i] {
+			return u * z.x[i]
+		}
+		if i == 0 {
+			return z.r - math.Log(1-r.Float64())
+		}
+		x := u * z.x[i]
+		if z.f[i]+r.Float64()*(z.f[i+1]-z.f[i]) < math.Exp(-x) {
+			return x
+		}
+	}
+}
+
+// Gamma returns a gamma variate with the given shape and rate, using
+// Marsaglia–Tsang with the U^(1/shape) boost for shape < 1.
+func (r *Rand) Gamma(shape, rate float64) float64 {
+	if shape < 1 {
+		return r.Gamma(shape+1, rate) * math.Pow(1-r.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := r.Normal()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := 1 - r.Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v / rate
+		}
+	}
+}
+
+func (r *Rand) Beta(alpha, beta float64) float64 {
+	x := r.Gamma(alpha, 1)
+	return x / (x + r.Gamma(beta, 1))
+}
+
+// Poisson uses multiplication of uniforms for lambda < 10 and Hörmann's
+// transformed rejection PTRS otherwise.
+func (r *Rand) Poisson(lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	if lambda < 10 {
+		limit := math.Exp(-lambda)
+		k, prod := 0, r.Float64()
+		for prod > limit {
+			k++
+			prod *= r.Float64()
+		}
+		return k
+	}
+	sqrtLambda := math.Sqrt(lambda)
+	logLambda := math.Log(lambda)
+	b := 0.931 + 2.53*sqrtLambda
+	a := -0.059 + 0.02483*b
+	invAlpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+	for {
+		u := r.Float64() - 0.5
+		v := r.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + 0.43)
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invAlpha)-math.Log(a/(us*us)+b) <= -lambda+k*logLambda-lg {
+			return int(k)
+		}
+	}
+}
+
+// Binomial uses inversion when n·min(p, 1-p) < 10 and Hörmann's transformed
+// rejection BTRS otherwise.
+func (r *Rand) Binomial(n int, p float64) int {
+	switch {
+	case n <= 0 || p <= 0:
+		return 0
+	case p >= 1:
+		return n
+	case p > 0.5:
+		return n - r.Binomial(n, 1-p)
+	}
+	nf := float64(n)
+	q := 1 - p
+	if nf*p < 10 {
+		pk := math.Pow(q, nf)
+		u := r.Float64()
+		for k := 0; ; k++ {
+			if u < pk || k == n {
+				return k
+			}
+			u -= pk
+			pk *= (nf - float64(k)) * p / (float64(k+1) * q)
+		}
+	}
+	spq := math.Sqrt(nf * p * q)
+	b := 1.15 + 2.53*spq
+	a := -0.0873 + 0.0248*b + 0.01*p
+	c := nf*p + 0.5
+	vr := 0.92 - 4.2/b
+	alpha := (2.83 + 5.1/b) * spq
+	lpq := math.Log(p / q)
+	m := math.Floor((nf + 1) * p)
+	lm, _ := math.Lgamma(m + 1)
+	lnm, _ := math.Lgamma(nf - m + 1)
+	for {
+		u := r.Float64() - 0.5
+		v := r.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + c)
+		if k < 0 || k > nf {
+			continue
+		}
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		lk, _ := math.Lgamma(k + 1)
+		lnk, _ := math.Lgamma(nf - k + 1)
+		if math.Log(v*alpha/(a/(us*us)+b)) <= lm+lnm-lk-lnk+(k-m)*lpq {
+			return int(k)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package main
 
 import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
 
 	calculus "github.com/mouaadid/MathsWithGolang/01_Calculus"
@@ -12,6 +19,9 @@ import (
 	algebra "github.com/mouaadid/MathsWithGolang/07_AlgebraicStructures"
 	arithmetic "github.com/mouaadid/MathsWithGolang/08_Arithmetic"
 	complexnums "github.com/mouaadid/MathsWithGolang/09_ComplexNumbers"
+	probability "github.com/mouaadid/MathsWithGolang/10_Probability"
+	optimization "github.com/mouaadid/MathsWithGolang/15_Optimization"
+	random "github.com/mouaadid/MathsWithGolang/16_Random"
 )
 
 func abs(x float64) float64 {
@@ -166,6 +176,2014 @@ func TestAlgebraModule(t *testing.T) {
 	}
 }
 
+func sameVector(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParallelMetaheuristics(t *testing.T) {
+	sphere := func(x []float64) float64 {
+		s := 0.0
+		for _, v := range x {
+			s += v * v
+		}
+		return s
+	}
+	bounds := [][2]float64{{-5, 5}, {-5, 5}, {-5, 5}}
+	pso := optimization.DefaultPSOSettings()
+	pso.Iterations = 60
+	de := optimization.DefaultDESettings(20, 60)
+	ga := optimization.DefaultGASettings(20, 60)
+	hs := optimization.DefaultHarmonySettings()
+	hs.Batch = 8
+	cem := optimization.DefaultCEMSettings(40, bounds)
+	cem.Iterations = 30
+	run := func(workers int) [][]float64 {
+		pso.Workers, de.Workers, ga.Workers, hs.Workers, cem.Workers = workers, workers, workers, workers, workers
+		return [][]float64{
+			optimization.PSOWithSettings(sphere, 3, 20, bounds, pso),
+			optimization.DifferentialEvolutionWithSettings(sphere, 3, bounds, de),
+			optimization.GeneticAlgorithmWithSettings(sphere, 3, bounds, ga),
+			optimization.HarmonySearch(sphere, 3, bounds, hs),
+			optimization.CrossEntropyMethod(sphere, []float64{1, 1, 1}, cem),
+		}
+	}
+	// PSO and DE (solvers 0 and 1) switch to synchronous updates above one
+	// worker, so they are compared against two workers; the rest must match
+	// the serial run for every worker count.
+	serial := run(0)
+	two := run(2)
+	for _, workers := range []int{1, 2, 6} {
+		parallel := run(workers)
+		for i := range serial {
+			want := serial[i]
+			if workers > 1 && i < 2 {
+				want = two[i]
+			}
+			if !sameVector(want, parallel[i]) {
+				t.Errorf("solver %d: result with %d workers %v differs from %v", i, workers, parallel[i], want)
+			}
+		}
+	}
+	for i := range serial {
+		if sphere(serial[i]) > 0.1 || sphere(two[i]) > 0.1 {
+			t.Errorf("solver %d: expected near-zero minimum, got %v and %v", i, serial[i], two[i])
+		}
+	}
+}
+
+func TestCMAESFull(t *testing.T) {
+	rotated := func(x []float64) float64 {
+		s := 0.0
+		partial := 0.0
+		for i, v := range x {
+			partial += v
+			s += float64(1+100*i) * partial * partial
+		}
+		return s
+	}
+	settings := optimization.DefaultCMAESFullSettings(nil)
+	x := optimization.CMAESFull(rotated, []float64{1, -1, 2, 0.5, -2}, settings)
+	if rotated(x) > 1e-8 {
+		t.Errorf("CMA-ES should solve the rotated ellipsoid, got f=%g", rotated(x))
+	}
+	settings.Restart = optimization.CMAESIPOP
+	settings.Bounds = [][2]float64{{-3, 3}, {-3, 3}, {-3, 3}, {-3, 3}, {-3, 3}}
+	x = optimization.CMAESFull(rotated, []float64{1, -1, 2, 0.5, -2}, settings)
+	if rotated(x) > 1e-8 {
+		t.Errorf("IPOP-CMA-ES should solve the rotated ellipsoid, got f=%g", rotated(x))
+	}
+}
+
+func TestMultiObjective(t *testing.T) {
+	hv := optimization.Hypervolume([][]float64{{1, 1, 1}, {0.5, 2, 2}}, []float64{3, 3, 3})
+	if abs(hv-8.5) > 1e-12 {
+		t.Errorf("hypervolume of two boxes should be 8.5, got %f", hv)
+	}
+	fronts := optimization.NonDominatedSort([][]float64{{1, 2}, {2, 1}, {2, 2}, {3, 3}})
+	if len(fronts) != 3 || len(fronts[0]) != 2 {
+		t.Errorf("unexpected non-dominated fronts %v", fronts)
+	}
+	zdt1 := func(x []float64) []float64 {
+		g := 0.0
+		for _, v := range x[1:] {
+			g += v
+		}
+		g = 1 + 9*g/float64(len(x)-1)
+		return []float64{x[0], g * (1 - math.Sqrt(x[0]/g))}
+	}
+	bounds := make([][2]float64, 6)
+	for i := range bounds {
+		bounds[i] = [2]float64{0, 1}
+	}
+	front := optimization.NSGA2(zdt1, 6, bounds, optimization.DefaultNSGA2Settings(60, 150))
+	points := make([][]float64, len(front))
+	for i, s := range front {
+		points[i] = s.F
+	}
+	if hv := optimization.Hypervolume(points, []float64{1, 1}); hv < 0.64 {
+		t.Errorf("NSGA-II front on ZDT1 should approach hypervolume 2/3, got %f", hv)
+	}
+
+	moead := optimization.DefaultMOEADSettings(100, 250)
+	front = optimization.MOEAD(zdt1, 6, bounds, moead)
+	points = points[:0]
+	for _, s := range front {
+		points = append(points, s.F)
+	}
+	if hv := optimization.Hypervolume(points, []float64{1, 1}); hv < 0.64 {
+		t.Errorf("MOEA/D front on ZDT1 should approach hypervolume 2/3, got %f", hv)
+	}
+	if w := optimization.SimplexLatticeWeights(3, 2); len(w) != 3 {
+		t.Errorf("the lattice keeps at least one division, got %v", w)
+	}
+	if w := optimization.SimplexLatticeWeights(3, 20); len(w) != 15 {
+		t.Errorf("four divisions of three objectives give 15 weights, got %d", len(w))
+	}
+	single := optimization.MOEAD(func(x []float64) []float64 { return []float64{x[0] * x[0]} }, 1, [][2]float64{{-1, 1}}, moead)
+	if len(single) != 1 || abs(single[0].X[0]) > 0.1 {
+		t.Errorf("MOEA/D on one objective should return its minimiser, got %v", single)
+	}
+}
+
+func TestCombinatorialProblems(t *testing.T) {
+	n := 12
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			a := 2 * math.Pi * float64(i) / float64(n)
+			b := 2 * math.Pi * float64(j) / float64(n)
+			dist[i][j] = math.Hypot(math.Cos(a)-math.Cos(b), math.Sin(a)-math.Sin(b))
+		}
+	}
+	best := 2 * float64(n) * math.Sin(math.Pi/float64(n))
+	tsp := optimization.PermutationProblem{Size: n, CostFunc: optimization.TourLength(dist), MutationKind: optimization.InversionMutationKind}
+	start := optimization.RandomPermutation(optimization.NewRNG(7), n)
+	anneal := optimization.DefaultAnnealSettings()
+	anneal.InitialTemp = 2
+	if tour := optimization.SimulatedAnnealingProblem(tsp, start, anneal); tsp.Cost(tour) > best+1e-9 {
+		t.Errorf("annealing should find the optimal circular tour %f, got %f", best, tsp.Cost(tour))
+	}
+	ga := optimization.DefaultGASettings(60, 200)
+	ga.MutationRate = 0.3
+	if tour := optimization.GeneticAlgorithmProblem(tsp, ga); tsp.Cost(tour) > best+1e-9 {
+		t.Errorf("GA with order crossover should find the optimal tour %f, got %f", best, tsp.Cost(tour))
+	}
+	knapsack := optimization.BitStringProblem{
+		Size:     5,
+		CostFunc: optimization.KnapsackCost([]float64{60, 100, 120, 30, 70}, []float64{10, 20, 30, 5, 25}, 50),
+	}
+	if pick := optimization.TabuSearchProblem(knapsack, make(optimization.BitString, 5), optimization.DefaultTabuSettings()); knapsack.Cost(pick) != -220 {
+		t.Errorf("knapsack optimum is 220, got %f", -knapsack.Cost(pick))
+	}
+}
+
+func TestBayesianOptimization(t *testing.T) {
+	gp := optimization.NewGaussianProcess(optimization.RBFKernel{LengthScale: 1, Variance: 1}, 1e-8)
+	if !gp.Fit([][]float64{{0}, {1}, {2}, {3}}, []float64{0, 1, 4, 9}) {
+		t.Fatal("GP fit should succeed")
+	}
+	if mean, variance := gp.Predict([]float64{2}); abs(mean-4) > 1e-4 || variance > 1e-4 {
+		t.Errorf("GP should interpolate training data, got mean=%f var=%g", mean, variance)
+	}
+	branin := func(x []float64) float64 {
+		b, c := 5.1/(4*math.Pi*math.Pi), 5/math.Pi
+		return math.Pow(x[1]-b*x[0]*x[0]+c*x[0]-6, 2) + 10*(1-1/(8*math.Pi))*math.Cos(x[0]) + 10
+	}
+	settings := optimization.DefaultBayesOptSettings()
+	settings.Iterations = 30
+	x := optimization.BayesianOptimization(branin, [][2]float64{{-5, 10}, {0, 15}}, settings)
+	if branin(x) > 0.42 {
+		t.Errorf("Bayesian optimization should approach Branin minimum 0.398, got %f", branin(x))
+	}
+}
+
+func TestSQP(t *testing.T) {
+	problem := optimization.SQPProblem{
+		F: func(x []float64) float64 { return x[0]*x[3]*(x[0]+x[1]+x[2]) + x[2] },
+		Eq: []optimization.ObjectiveFunc{
+			func(x []float64) float64 { return x[0]*x[0] + x[1]*x[1] + x[2]*x[2] + x[3]*x[3] - 40 },
+		},
+		Ineq: []optimization.ObjectiveFunc{
+			func(x []float64) float64 { return 25 - x[0]*x[1]*x[2]*x[3] },
+		},
+		Bounds: [][2]float64{{1, 5}, {1, 5}, {1, 5}, {1, 5}},
+	}
+	result := optimization.SQP(problem, []float64{1, 5, 5, 1}, optimization.DefaultSQPSettings())
+	if abs(result.Value-17.0140173) > 1e-6 {
+		t.Errorf("HS071 optimum should be 17.0140173, got %f", result.Value)
+	}
+	if result.KKTViolation > 1e-5 || abs(result.LambdaIneq[0]-0.5522937) > 1e-4 {
+		t.Errorf("unexpected multipliers or KKT violation: %+v", result)
+	}
+}
+
+func TestNonlinearSystems(t *testing.T) {
+	F := func(x []float64) []float64 {
+		return []float64{x[0]*x[0] + x[1]*x[1] - 4, math.Exp(x[0]) + x[1] - 1}
+	}
+	J := func(x []float64) [][]float64 {
+		return [][]float64{{2 * x[0], 2 * x[1]}, {math.Exp(x[0]), 1}}
+	}
+	settings := optimization.DefaultRootSettings()
+	results := []optimization.SystemRootResult{
+		optimization.NewtonSystem(F, J, []float64{1, -1}, settings),
+		optimization.NewtonSystem(F, nil, []float64{1, -1}, settings),
+		optimization.BroydenSystem(F, nil, []float64{1, -1}, optimization.BroydenGood, settings),
+		optimization.BroydenSystem(F, nil, []float64{1, -1}, optimization.BroydenBad, settings),
+		optimization.DoglegSystem(F, J, []float64{1, -1}, settings),
+	}
+	for i, r := range results {
+		if !r.Converged || r.Residual > 1e-9 || abs(r.Root[0]-1.0041687) > 1e-6 {
+			t.Errorf("solver %d did not converge to (1.00417, -1.72964): %+v", i, r)
+		}
+	}
+}
+
+func TestBenchmarkSuite(t *testing.T) {
+	functions := append(optimization.StandardBenchmarks(3), optimization.HimmelblauBenchmark())
+	for _, b := range functions {
+		if abs(b.F(b.Optimum)-b.OptimumValue) > 1e-9 {
+			t.Errorf("%s: value at optimum %f, want %f", b.Name, b.F(b.Optimum), b.OptimumValue)
+		}
+		for _, g := range b.Grad(b.Optimum) {
+			if abs(g) > 1e-6 {
+				t.Errorf("%s: gradient at optimum should vanish, got %v", b.Name, b.Grad(b.Optimum))
+				break
+			}
+		}
+	}
+	// Central differences at random points inside the recommended bounds,
+	// where the gradient is not zero by construction.
+	checked := append(functions,
+		optimization.ShiftedRotatedBenchmark(optimization.AckleyBenchmark(3), 3),
+		optimization.ShiftedRotatedBenchmark(optimization.GriewankBenchmark(3), 5),
+		optimization.ShiftedRotatedBenchmark(optimization.StyblinskiTangBenchmark(3), 9))
+	rng := rand.New(rand.NewPCG(33, 34))
+	for _, b := range checked {
+		for trial := 0; trial < 5; trial++ {
+			x := make([]float64, b.Dim)
+			for i, bd := range b.Bounds {
+				x[i] = bd[0] + (bd[1]-bd[0])*rng.Float64()
+			}
+			g := b.Grad(x)
+			for i := range x {
+				h := 1e-6 * math.Max(1, math.Abs(x[i]))
+				xp := append([]float64{}, x...)
+				xm := append([]float64{}, x...)
+				xp[i] += h
+				xm[i] -= h
+				fd := (b.F(xp) - b.F(xm)) / (2 * h)
+				if abs(g[i]-fd) > 1e-4*math.Max(1, abs(fd)) {
+					t.Errorf("%s: ∂f/∂x%d at %v = %g, central difference %g", b.Name, i, x, g[i], fd)
+				}
+			}
+		}
+	}
+
+	settings := optimization.DefaultBenchmarkSettings()
+	settings.Seeds = []uint64{1, 2}
+	records := optimization.RunBenchmarks(optimization.DefaultBenchmarkSolvers()[:2], functions[:1], settings)
+	if len(records) != 2 || records[0].SuccessRate != 1 || records[1].SuccessRate != 1 {
+		t.Errorf("Nelder-Mead and BFGS should always solve the sphere: %+v", records)
+	}
+	if csv := optimization.BenchmarkCSV(records); strings.Count(csv, "\n") != 3 {
+		t.Errorf("CSV should have a header and one row per record:\n%s", csv)
+	}
+}
+
+func TestProximalMethods(t *testing.T) {
+	A := [][]float64{{1, 2, 0, 1}, {0, 1, 3, 1}, {2, 0, 1, 0}, {1, 1, 1, 1}, {0, 2, 1, 3}, {3, 1, 0, 2}}
+	b := []float64{1, 2, 3, 4, 5, 6}
+	residual := func(x []float64) []float64 {
+		r := make([]float64, len(A))
+		for i, row := range A {
+			r[i] = -b[i]
+			for j, a := range row {
+				r[i] += a * x[j]
+			}
+		}
+		return r
+	}
+	f := func(x []float64) float64 {
+		s := 0.0
+		for _, v := range residual(x) {
+			s += v * v / 2
+		}
+		return s
+	}
+	grad := func(x []float64) []float64 {
+		r := residual(x)
+		g := make([]float64, len(x))
+		for i, row := range A {
+			for j, a := range row {
+				g[j] += a * r[i]
+			}
+		}
+		return g
+	}
+	lasso := optimization.L1Prox{Lambda: 0.8}
+	x0 := make([]float64, 4)
+	ista := optimization.ISTA(f, grad, lasso, x0, optimization.DefaultProximalSettings())
+	fista := optimization.FISTA(f, grad, lasso, x0, optimization.DefaultProximalSettings())
+	admm := optimization.ADMM(optimization.ADMMProblem{F: optimization.LeastSquaresProx(A, b), G: lasso}, x0, optimization.DefaultADMMSettings())
+	if !ista.Converged || !fista.Converged || fista.Iterations >= ista.Iterations {
+		t.Errorf("FISTA should converge faster than ISTA: %d vs %d iterations", fista.Iterations, ista.Iterations)
+	}
+	for i := range fista.X {
+		if abs(fista.X[i]-ista.X[i]) > 1e-6 || abs(fista.X[i]-admm.Z[i]) > 1e-5 {
+			t.Errorf("lasso solutions disagree: ISTA %v, FISTA %v, ADMM %v", ista.X, fista.X, admm.Z)
+			break
+		}
+	}
+	// Splitting the rows of A into two blocks gives the same lasso problem.
+	consensus := optimization.ConsensusADMM([]optimization.ProxOperator{
+		optimization.LeastSquaresProx(A[:3], b[:3]),
+		optimization.LeastSquaresProx(A[3:], b[3:]),
+	}, lasso, x0, optimization.DefaultADMMSettings())
+	for i := range admm.Z {
+		if !consensus.Converged || abs(consensus.Z[i]-admm.Z[i]) > 1e-5 {
+			t.Errorf("consensus ADMM %v should match single-block ADMM %v", consensus.Z, admm.Z)
+			break
+		}
+	}
+
+	y := []float64{0, 0.1, -0.1, 0.05, 1, 1.1, 0.9, 1.05, 1, 0}
+	D := make([][]float64, len(y)-1)
+	identity := make([][]float64, len(y))
+	for i := range identity {
+		identity[i] = make([]float64, len(y))
+		identity[i][i] = 1
+	}
+	for i := range D {
+		D[i] = make([]float64, len(y))
+		D[i][i], D[i][i+1] = -1, 1
+	}
+	tv := optimization.ADMM(optimization.ADMMProblem{F: optimization.QuadraticProx{Q: identity, B: y}, G: optimization.L1Prox{Lambda: 0.2}, A: D}, make([]float64, len(y)), optimization.DefaultADMMSettings())
+	if !tv.Converged || abs(tv.X[0]-0.0625) > 1e-5 || abs(tv.X[5]-0.93) > 1e-5 || abs(tv.X[9]-0.2) > 1e-5 {
+		t.Errorf("TV denoising should give piecewise-constant levels 0.0625, 0.93, 0.2, got %v", tv.X)
+	}
+
+	p := optimization.EuclideanSimplexProjection([]float64{0.5, 0.9, -1})
+	if abs(p[0]-0.3) > 1e-12 || abs(p[1]-0.7) > 1e-12 || p[2] != 0 {
+		t.Errorf("simplex projection of (0.5, 0.9, -1) should be (0.3, 0.7, 0), got %v", p)
+	}
+	nuclear := optimization.NuclearNormProx{Lambda: 1, Rows: 2, Cols: 3}
+	shrunk := nuclear.Prox([]float64{3, 0, 0, 0, 0.5, 0}, 1)
+	if abs(shrunk[0]-2) > 1e-9 || abs(shrunk[4]) > 1e-9 {
+		t.Errorf("nuclear prox should shrink singular values (3, 0.5) to (2, 0), got %v", shrunk)
+	}
+
+	group := optimization.GroupLassoProx{Lambda: 1, Groups: [][]int{{0, 1}, {2}}}
+	if g := group.Prox([]float64{3, 4, 0.5, 7}, 1); abs(g[0]-2.4) > 1e-12 || abs(g[1]-3.2) > 1e-12 || g[2] != 0 || g[3] != 7 || group.Value([]float64{3, 4, 0.5, 7}) != 5.5 {
+		t.Errorf("group prox should scale (3, 4) by 4/5, zero 0.5 and leave ungrouped 7, got %v", g)
+	}
+	box := optimization.BoxProx{Bounds: [][2]float64{{0, 1}, {-1, 1}}}
+	if x := box.Prox([]float64{2, -3}, 5); !sameVector(x, []float64{1, -1}) || box.Value([]float64{0.5, 0}) != 0 || !math.IsInf(box.Value([]float64{2, 0}), 1) {
+		t.Errorf("box prox should clip (2, -3) to (1, -1), got %v", x)
+	}
+	// For g(x) = ||x - c||², prox(v, t) = (v + 2tc) / (1 + 2t).
+	c := []float64{1, -1}
+	sq := func(x []float64) float64 {
+		return (x[0]-c[0])*(x[0]-c[0]) + (x[1]-c[1])*(x[1]-c[1])
+	}
+	sqGrad := func(x []float64) []float64 { return []float64{2 * (x[0] - c[0]), 2 * (x[1] - c[1])} }
+	for _, smooth := range []optimization.SmoothProx{{F: sq, Grad: sqGrad}, {F: sq}} {
+		if x := smooth.Prox([]float64{3, 3}, 0.5); abs(x[0]-2) > 1e-6 || abs(x[1]-1) > 1e-6 {
+			t.Errorf("smooth prox of (3, 3) should be (2, 1), got %v", x)
+		}
+	}
+}
+
+func TestMiniBatchTraining(t *testing.T) {
+	n := 200
+	X := make([][]float64, n)
+	y := make([]float64, n)
+	for i := range X {
+		u, v := math.Sin(float64(i)), math.Cos(float64(3*i))
+		X[i] = []float64{u, v, 1}
+		y[i] = 2*u - 3*v + 0.5
+	}
+	sample := func(w []float64, i int) []float64 {
+		r := w[0]*X[i][0] + w[1]*X[i][1] + w[2] - y[i]
+		return []float64{r * X[i][0], r * X[i][1], r}
+	}
+	loss := func(w []float64) float64 {
+		s := 0.0
+		for i := range X {
+			r := w[0]*X[i][0] + w[1]*X[i][1] + w[2] - y[i]
+			s += r * r
+		}
+		return s / float64(n)
+	}
+	grad := optimization.SampleGradBatch(sample)
+	kinds := []optimization.TrainingOptimizer{optimization.TrainNesterov, optimization.TrainAdaGrad, optimization.TrainAdamW, optimization.TrainNadam}
+	for _, kind := range kinds {
+		settings := optimization.DefaultTrainingSettings(n)
+		settings.Optimizer = kind
+		settings.Epochs = 300
+		settings.WeightDecay = 0
+		settings.Validation = loss
+		settings.ClipNorm = 10
+		settings.Schedule = optimization.WarmupLR{WarmupSteps: 10, After: optimization.CosineLR{Base: 0.1, Min: 1e-4, TotalSteps: 2000}}
+		if kind == optimization.TrainAdaGrad {
+			settings.Schedule = optimization.ConstantLR{Value: 0.5}
+		}
+		result := optimization.TrainMiniBatch(grad, []float64{0, 0, 0}, settings)
+		if result.BestLoss > 1e-6 || abs(result.X[0]-2) > 1e-3 || abs(result.X[1]+3) > 1e-3 {
+			t.Errorf("optimizer %d should recover (2, -3, 0.5), got %v with loss %g", kind, result.X, result.BestLoss)
+		}
+	}
+
+	plateau := optimization.NewPlateauLR(0.1, 0.5, 1)
+	for epoch, l := range []float64{1, 0.5, 0.5, 0.5, 0.5} {
+		plateau.Observe(epoch, l)
+	}
+	if abs(plateau.Rate(0, 5)-0.05) > 1e-12 {
+		t.Errorf("plateau schedule should halve the rate once, got %f", plateau.Rate(0, 5))
+	}
+	settings := optimization.DefaultTrainingSettings(n)
+	settings.Schedule = optimization.StepLR{Base: 0.01, Gamma: 0.5, StepEpochs: 5}
+	settings.Validation = func(w []float64) float64 { return 1 }
+	settings.Patience = 3
+	result := optimization.TrainMiniBatch(grad, []float64{0, 0, 0}, settings)
+	if !result.StoppedEarly || result.Epochs != 4 || result.BestEpoch != 0 {
+		t.Errorf("training should stop after 3 epochs without improvement: %+v", result)
+	}
+	clipped := optimization.ClipGradientNorm([]float64{3, 4}, 1)
+	if abs(clipped[0]-0.6) > 1e-12 || abs(clipped[1]-0.8) > 1e-12 {
+		t.Errorf("norm clipping of (3, 4) to 1 should give (0.6, 0.8), got %v", clipped)
+	}
+}
+
+func TestStrongWolfeLineSearch(t *testing.T) {
+	rosen := optimization.RosenbrockBenchmark(4)
+	x := []float64{-1.2, 1, -1.2, 1}
+	g := rosen.Grad(x)
+	p := make([]float64, len(g))
+	slope := 0.0
+	for i := range g {
+		p[i] = -g[i]
+		slope -= g[i] * g[i]
+	}
+	res := optimization.MoreThuenteLineSearch(rosen.F, rosen.Grad, x, p, rosen.F(x), g, 1e-4, 0.9)
+	newSlope := 0.0
+	for i := range p {
+		newSlope += res.Grad[i] * p[i]
+	}
+	if !res.Converged || res.F > rosen.F(x)+1e-4*res.Alpha*slope || abs(newSlope) > 0.9*abs(slope) {
+		t.Errorf("step %f violates the strong Wolfe conditions", res.Alpha)
+	}
+
+	bfgs := optimization.DefaultBFGSSettings()
+	bfgs.LineSearch = optimization.LineSearchStrongWolfe
+	lbfgs := optimization.DefaultLBFGSSettings()
+	lbfgs.LineSearch = optimization.LineSearchStrongWolfe
+	nlcg := optimization.DefaultNLCGSettings()
+	nlcg.LineSearch = optimization.LineSearchStrongWolfe
+	results := [][]float64{
+		optimization.BFGSWithSettings(rosen.F, rosen.Grad, x, bfgs),
+		optimization.LBFGS(rosen.F, rosen.Grad, x, lbfgs),
+		optimization.NonlinearConjugateGradient(rosen.F, rosen.Grad, x, nlcg),
+	}
+	for i, r := range results {
+		if rosen.F(r) > 1e-12 {
+			t.Errorf("method %d with strong Wolfe search should reach the Rosenbrock minimum, got f=%g", i, rosen.F(r))
+		}
+	}
+
+	// Every accepted step reuses the value and gradient from the search, so
+	// f and its gradient are evaluated the same number of times.
+	fCalls, gradCalls := 0, 0
+	counted := optimization.BFGSWithSettings(
+		func(x []float64) float64 { fCalls++; return rosen.F(x) },
+		func(x []float64) []float64 { gradCalls++; return rosen.Grad(x) },
+		x, bfgs)
+	if !sameVector(counted, results[0]) || fCalls != gradCalls {
+		t.Errorf("strong Wolfe BFGS made %d function and %d gradient calls", fCalls, gradCalls)
+	}
+}
+
+func TestLBFGSB(t *testing.T) {
+	quad := func(x []float64) float64 {
+		s := 0.0
+		for i, v := range x {
+			d := v - float64(i) + 2
+			s += float64(i+1) * d * d
+		}
+		return s
+	}
+	grad := func(x []float64) []float64 {
+		g := make([]float64, len(x))
+		for i, v := range x {
+			g[i] = 2 * float64(i+1) * (v - float64(i) + 2)
+		}
+		return g
+	}
+	bounds := [][2]float64{{0, 2.5}, {0, 2.5}, {0, math.Inf(1)}, {math.Inf(-1), 2.5}, {0, 2.5}, {0, 2.5}}
+	x := optimization.LBFGSB(quad, grad, make([]float64, 6), bounds, optimization.DefaultLBFGSBSettings())
+	want := []float64{0, 0, 0, 1, 2, 2.5}
+	for i := range want {
+		if abs(x[i]-want[i]) > 1e-8 {
+			t.Errorf("L-BFGS-B should clip the quadratic minimum to %v, got %v", want, x)
+			break
+		}
+	}
+	rosen := optimization.RosenbrockBenchmark(10)
+	start := make([]float64, 10)
+	for i := range start {
+		start[i] = -1.2
+	}
+	x = optimization.LBFGSB(rosen.F, rosen.Grad, start, nil, optimization.DefaultLBFGSBSettings())
+	if rosen.F(x) > 1e-12 {
+		t.Errorf("unbounded L-BFGS-B should solve Rosenbrock, got f=%g", rosen.F(x))
+	}
+}
+
+func TestCheckpointResume(t *testing.T) {
+	rastrigin := optimization.RastriginBenchmark(4)
+	f, bounds := rastrigin.F, rastrigin.Bounds
+	for _, format := range []optimization.CheckpointFormat{optimization.CheckpointJSON, optimization.CheckpointBinary} {
+		var mid, fullEnd, resumedEnd bytes.Buffer
+		capture := func(at int, final *bytes.Buffer) optimization.CheckpointSettings {
+			return optimization.CheckpointSettings{Every: 1, Handler: func(state optimization.CheckpointState) {
+				final.Reset()
+				if err := state.Save(final, format); err != nil {
+					t.Fatal(err)
+				}
+				if at > 0 && mid.Len() == 0 {
+					at--
+					if at == 0 {
+						mid.Write(final.Bytes())
+					}
+				}
+			}}
+		}
+		// Each solver is checkpointed after its at-th iteration; resuming from
+		// there must reproduce the uninterrupted result and final state.
+		var (
+			psoState  optimization.PSOState
+			deState   optimization.DEState
+			gaState   optimization.GAState
+			cemState  optimization.CEMState
+			diagState optimization.CMAESDiagonalState
+			cmaState  optimization.CMAESFullState
+			saState   optimization.AnnealState
+			tabuState optimization.TabuState
+		)
+		pso := optimization.DefaultPSOSettings()
+		pso.Iterations = 60
+		de := optimization.DefaultDESettings(20, 80)
+		ga := optimization.DefaultGASettings(20, 60)
+		cem := optimization.DefaultCEMSettings(30, bounds)
+		cem.Iterations = 40
+		diag := optimization.DefaultCMAESSettings(12, bounds)
+		cma := optimization.DefaultCMAESFullSettings(bounds)
+		cma.Restart = optimization.CMAESBIPOP
+		cma.MaxEvaluations = 20000
+		sa := optimization.DefaultAnnealSettings()
+		sa.Iterations = 50
+		tabu := optimization.DefaultTabuSettings()
+		start := []float64{1, 2, -1, 3}
+		cases := []struct {
+			name   string
+			at     int
+			state  optimization.CheckpointState
+			run    func(optimization.CheckpointSettings) []float64
+			resume func(optimization.CheckpointSettings) []float64
+		}{
+			{"PSO", 30, &psoState,
+				func(c optimization.CheckpointSettings) []float64 {
+					pso.Checkpoint = c
+					return optimization.PSOWithSettings(f, 4, 20, bounds, pso)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					pso.Checkpoint = c
+					return optimization.PSOResume(f, bounds, &psoState, pso)
+				}},
+			{"DE", 30, &deState,
+				func(c optimization.CheckpointSettings) []float64 {
+					de.Checkpoint = c
+					return optimization.DifferentialEvolutionWithSettings(f, 4, bounds, de)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					de.Checkpoint = c
+					return optimization.DifferentialEvolutionResume(f, bounds, &deState, de)
+				}},
+			{"GA", 25, &gaState,
+				func(c optimization.CheckpointSettings) []float64 {
+					ga.Checkpoint = c
+					return optimization.GeneticAlgorithmWithSettings(f, 4, bounds, ga)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					ga.Checkpoint = c
+					return optimization.GeneticAlgorithmResume(f, bounds, &gaState, ga)
+				}},
+			{"CEM", 15, &cemState,
+				func(c optimization.CheckpointSettings) []float64 {
+					cem.Checkpoint = c
+					return optimization.CrossEntropyMethod(f, []float64{1, 1, 1, 1}, cem)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					cem.Checkpoint = c
+					return optimization.CrossEntropyMethodResume(f, &cemState, cem)
+				}},
+			{"CMAESDiagonal", 60, &diagState,
+				func(c optimization.CheckpointSettings) []float64 {
+					diag.Checkpoint = c
+					return optimization.CMAESDiagonal(f, append([]float64{}, start...), diag)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					diag.Checkpoint = c
+					return optimization.CMAESDiagonalResume(f, &diagState, diag)
+				}},
+			{"CMAESFull", 300, &cmaState,
+				func(c optimization.CheckpointSettings) []float64 {
+					cma.Checkpoint = c
+					return optimization.CMAESFull(f, start, cma)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					cma.Checkpoint = c
+					return optimization.CMAESFullResume(f, &cmaState, cma)
+				}},
+			{"SimulatedAnnealing", 40, &saState,
+				func(c optimization.CheckpointSettings) []float64 {
+					sa.Checkpoint = c
+					return optimization.SimulatedAnnealingWithSettings(f, start, bounds, sa)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					sa.Checkpoint = c
+					return optimization.SimulatedAnnealingResume(f, bounds, &saState, sa)
+				}},
+			{"TabuSearch", 150, &tabuState,
+				func(c optimization.CheckpointSettings) []float64 {
+					tabu.Checkpoint = c
+					return optimization.TabuSearchWithSettings(f, start, bounds, tabu)
+				},
+				func(c optimization.CheckpointSettings) []float64 {
+					tabu.Checkpoint = c
+					return optimization.TabuSearchResume(f, bounds, &tabuState, tabu)
+				}},
+		}
+		for _, tc := range cases {
+			mid.Reset()
+			full := tc.run(capture(tc.at, &fullEnd))
+			if err := tc.state.Load(&mid, format); err != nil {
+				t.Fatalf("format %d: failed to load the %s checkpoint: %v", format, tc.name, err)
+			}
+			resumed := tc.resume(capture(0, &resumedEnd))
+			if !sameVector(full, resumed) || !bytes.Equal(fullEnd.Bytes(), resumedEnd.Bytes()) {
+				t.Errorf("format %d: resumed %s run differs from the uninterrupted one", format, tc.name)
+			}
+		}
+		if deState.Generation != 80 || cmaState.Restarts == 0 {
+			t.Errorf("format %d: DE resumed to generation %d, CMA-ES checkpoint had %d restarts", format, deState.Generation, cmaState.Restarts)
+		}
+	}
+
+	var buf bytes.Buffer
+	state := optimization.AnnealState{BestValue: math.Inf(1), X: []float64{0.1, -2e-300}, RNG: 1<<63 + 12345}
+	if err := state.Save(&buf, optimization.CheckpointJSON); err != nil {
+		t.Fatal(err)
+	}
+	var back optimization.AnnealState
+	if err := back.Load(&buf, optimization.CheckpointJSON); err != nil || !math.IsInf(back.BestValue, 1) || back.RNG != state.RNG || !sameVector(back.X, state.X) {
+		t.Errorf("JSON round trip lost precision: %+v (%v)", back, err)
+	}
+}
+
+func TestDistributions(t *testing.T) {
+	critical := []struct {
+		d    probability.Distribution
+		p, x float64
+	}{
+		{probability.Normal{Mu: 0, Sigma: 1}, 0.975, 1.959963984540054},
+		{probability.StudentT{Nu: 10}, 0.975, 2.228138851986274},
+		{probability.ChiSquared{K: 3}, 0.95, 7.814727903251178},
+		{probability.FisherF{D1: 3, D2: 20}, 0.95, 3.0983912121407799},
+		{probability.Gamma{Shape: 2, Rate: 1}, 0.5, 1.678346990016661},
+	}
+	for _, c := range critical {
+		if q := c.d.Quantile(c.p); abs(q-c.x) > 1e-10*c.x {
+			t.Errorf("%T quantile(%g) = %.15g, want %.15g", c.d, c.p, q, c.x)
+		}
+	}
+
+	rng := probability.NewLCG(7)
+	continuous := []probability.Distribution{
+		probability.Normal{Mu: 1, Sigma: 2}, probability.LogNormal{Mu: 0.3, Sigma: 0.5},
+		probability.Exponential{Rate: 1.5}, probability.Gamma{Shape: 0.4, Rate: 1},
+		probability.Beta{Alpha: 2, Beta: 5}, probability.ChiSquared{K: 3},
+		probability.StudentT{Nu: 5}, probability.FisherF{D1: 5, D2: 10},
+		probability.Weibull{Shape: 1.5, Scale: 2}, probability.Cauchy{Location: 0, Scale: 1},
+		probability.Laplace{Mu: 1, B: 2}, probability.Logistic{Mu: 1, S: 0.5},
+		probability.Pareto{Xm: 1, Alpha: 3}, probability.Uniform{A: -1, B: 3},
+	}
+	for _, d := range continuous {
+		for _, p := range []float64{0.001, 0.3, 0.5, 0.9} {
+			x := d.Quantile(p)
+			if abs(d.CDF(x)-p) > 1e-12 || abs(d.Survival(x)-(1-p)) > 1e-12 {
+				t.Errorf("%T CDF(Quantile(%g)) = %g", d, p, d.CDF(x))
+			}
+			if lp := d.LogPDF(x); abs(math.Exp(lp)-d.PDF(x)) > 1e-12*d.PDF(x) {
+				t.Errorf("%T LogPDF inconsistent with PDF at %g", d, x)
+			}
+		}
+		x := d.Quantile(0.4)
+		h := 1e-5 * math.Max(1, abs(x))
+		if slope := (d.CDF(x+h) - d.CDF(x-h)) / (2 * h); abs(slope-d.PDF(x)) > 1e-6*d.PDF(x) {
+			t.Errorf("%T PDF %g does not match CDF slope %g", d, d.PDF(x), slope)
+		}
+		entropy := 0.0
+		for i := 1; i < 20000; i++ {
+			entropy -= d.LogPDF(d.Quantile(float64(i) / 20000))
+		}
+		if entropy /= 19999; abs(entropy-d.Entropy()) > 5e-3*math.Max(1, abs(d.Entropy())) {
+			t.Errorf("%T entropy %g, numerical %g", d, d.Entropy(), entropy)
+		}
+		if math.IsNaN(d.Variance()) || math.IsInf(d.Variance(), 0) {
+			continue
+		}
+		sum := 0.0
+		for i := 0; i < 20000; i++ {
+			sum += d.Sample(rng)
+		}
+		if mean := sum / 20000; abs(mean-d.Mean()) > 4*math.Sqrt(d.Variance()/20000) {
+			t.Errorf("%T sample mean %g, want %g", d, mean, d.Mean())
+		}
+	}
+
+	discrete := []probability.DiscreteDistribution{
+		probability.Binomial{N: 20, P: 0.3}, probability.Poisson{Lambda: 3.5},
+		probability.Geometric{P: 0.2}, probability.NegativeBinomial{R: 3, P: 0.4},
+		probability.Hypergeometric{Population: 50, Successes: 10, Draws: 12},
+	}
+	for _, d := range discrete {
+		mass, mean, second, entropy := 0.0, 0.0, 0.0, 0.0
+		for k := d.Quantile(0); k < 200; k++ {
+			p := d.PMF(k)
+			mass += p
+			mean += float64(k) * p
+			second += float64(k) * float64(k) * p
+			if p > 0 {
+				entropy -= p * math.Log(p)
+			}
+			if abs(d.CDF(k)-mass) > 1e-12 || abs(d.Survival(k)-(1-mass)) > 1e-12 {
+				t.Errorf("%T CDF(%d) = %g, summed PMF %g", d, k, d.CDF(k), mass)
+				break
+			}
+		}
+		if abs(mean-d.Mean()) > 1e-9 || abs(second-mean*mean-d.Variance()) > 1e-9 || abs(entropy-d.Entropy()) > 1e-9 {
+			t.Errorf("%T moments or entropy disagree with the PMF", d)
+		}
+		if q := d.Quantile(0.5); d.CDF(q) < 0.5 || d.CDF(q-1) >= 0.5 {
+			t.Errorf("%T median %d is not the smallest k with CDF >= 0.5", d, q)
+		}
+		sum := 0.0
+		for i := 0; i < 20000; i++ {
+			sum += float64(d.Sample(rng))
+		}
+		if m := sum / 20000; abs(m-d.Mean()) > 4*math.Sqrt(d.Variance()/20000) {
+			t.Errorf("%T sample mean %g, want %g", d, m, d.Mean())
+		}
+	}
+	if b := (probability.Binomial{N: 12, P: 0.35}); abs(b.CDF(5)-probability.BinomialCDF(12, 5, 0.35)) > 1e-6 {
+		t.Errorf("Binomial.CDF disagrees with BinomialCDF")
+	}
+}
+
+func relErr(got, want float64) float64 {
+	if want == 0 {
+		return abs(got)
+	}
+	return abs(got-want) / abs(want)
+}
+
+func TestIncompleteGammaBeta(t *testing.T) {
+	// Reference values computed to 40 digits.
+	gammaCases := [][4]float64{
+		{0.5, 0.1, 0.34527915398142295, 0.65472084601857705},
+		{0.5, 3, 0.98569412156457037, 0.01430587843542964},
+		{1.5, 0.7, 0.29446526879590884, 0.70553473120409116},
+		{3, 2.5, 0.45618688411667047, 0.54381311588332948},
+		{10, 25, 0.99977852336175121, 0.00022147663824878357},
+		{25.5, 20, 0.13312255797599271, 0.86687744202400729},
+		{100, 90, 0.15822098918643016, 0.84177901081356987},
+		{100, 130, 0.99724959163269344, 0.0027504083673065261},
+		{0.1, 0.01, 0.66262125995447985, 0.33737874004552021},
+		{7.3, 0.2, 7.1385796260952339e-10, 0.99999999928614203},
+		{500, 480, 0.18628197319032461, 0.81371802680967542},
+		{2, 60, 1, 5.3414715652448774e-25},
+	}
+	for _, c := range gammaCases {
+		a, x, p, q := c[0], c[1], c[2], c[3]
+		if e := math.Max(relErr(functions.RegularizedGammaP(a, x), p), relErr(functions.RegularizedGammaQ(a, x), q)); e > 1e-12 {
+			t.Errorf("P/Q(%g, %g) relative error %g", a, x, e)
+		}
+		inverse := functions.InverseRegularizedGammaP(a, p)
+		if q < p {
+			inverse = functions.InverseRegularizedGammaQ(a, q)
+		}
+		if e := relErr(inverse, x); e > 1e-12 {
+			t.Errorf("inverse incomplete gamma at (%g, %g) relative error %g", a, x, e)
+		}
+	}
+
+	betaCases := [][5]float64{
+		{0.5, 0.5, 0.3, 0.36901011956554536, 0.63098988043445459},
+		{2, 3, 0.4, 0.5248, 0.4752},
+		{1.5, 7.25, 0.05, 0.14303295721639325, 0.85696704278360669},
+		{10, 20, 0.3, 0.36400408107194426, 0.63599591892805574},
+		{50, 60, 0.48, 0.7052077196580101, 0.2947922803419899},
+		{0.2, 3, 0.9, 0.99990630764302946, 9.3692356970502416e-05},
+		{200, 150, 0.55, 0.2084878008596249, 0.79151219914037507},
+		{5, 0.5, 0.99, 0.75715810910156245, 0.2428418908984375},
+		{30, 2.5, 0.7, 0.00054354607822469962, 0.99945645392177529},
+		{400, 0.5, 0.998, 0.2058179739269321, 0.79418202607306787},
+	}
+	for _, c := range betaCases {
+		a, b, x, w, wc := c[0], c[1], c[2], c[3], c[4]
+		if e := math.Max(relErr(functions.RegularizedBeta(a, b, x), w), relErr(functions.RegularizedBeta(b, a, 1-x), wc)); e > 1e-12 {
+			t.Errorf("I_%g(%g, %g) relative error %g", x, a, b, e)
+		}
+		if e := relErr(functions.InverseRegularizedBeta(a, b, w), x); e > 1e-12 {
+			t.Errorf("InverseRegularizedBeta(%g, %g, %g) relative error %g", a, b, w, e)
+		}
+	}
+
+	for _, a := range []float64{0.3, 1, 2.5, 10, 37, 150, 2000, 1e5} {
+		for _, p := range []float64{1e-12, 1e-3, 0.2, 0.5, 0.8, 1 - 1e-9} {
+			x := functions.InverseRegularizedGammaP(a, p)
+			e := relErr(functions.RegularizedGammaP(a, x), p)
+			if p > 0.5 {
+				e = relErr(functions.RegularizedGammaQ(a, x), 1-p)
+			}
+			if e > 1e-12 {
+				t.Errorf("P(%g, P⁻¹(%g)) relative error %g", a, p, e)
+			}
+		}
+	}
+	for _, a := range []float64{0.5, 1, 3, 12, 150} {
+		for _, b := range []float64{0.5, 2, 11, 500} {
+			for _, p := range []float64{1e-12, 1e-3, 0.2, 0.5, 0.8} {
+				x := functions.InverseRegularizedBeta(a, b, p)
+				e := relErr(functions.RegularizedBeta(a, b, x), p)
+				if p > 0.5 {
+					e = relErr(functions.RegularizedBeta(b, a, 1-x), 1-p)
+				}
+				if e > 1e-12 {
+					t.Errorf("I(%g, %g) round trip at %g relative error %g", a, b, p, e)
+				}
+			}
+		}
+	}
+}
+
+func TestQuantileFunctions(t *testing.T) {
+	// Reference values computed to 40 digits.
+	cases := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"chi2(1) 0.95", probability.ChiSquareQuantile(0.95, 1), 3.8414588206941258},
+		{"chi2(2) 0.95", probability.ChiSquareQuantile(0.95, 2), 5.9914645471079817},
+		{"chi2(5) 0.99", probability.ChiSquareQuantile(0.99, 5), 15.086272469388991},
+		{"chi2(10) 0.05", probability.ChiSquareQuantile(0.05, 10), 3.94029913611906},
+		{"chi2(30) 0.95", probability.ChiSquareQuantile(0.95, 30), 43.772971825742189},
+		{"chi2(100) 0.999", probability.ChiSquareQuantile(0.999, 100), 149.44925277903872},
+		{"chi2(3) 1e-6", probability.ChiSquareQuantile(1e-6, 3), 0.00024181048720124283},
+		{"t(1) 0.975", probability.TDistQuantile(0.975, 1), 12.706204736174705},
+		{"t(2) 0.975", probability.TDistQuantile(0.975, 2), 4.3026527297494637},
+		{"t(5) 0.995", probability.TDistQuantile(0.995, 5), 4.0321429835552278},
+		{"t(30) 0.975", probability.TDistQuantile(0.975, 30), 2.0422724563012382},
+		{"t(4) 1e-8", probability.TDistQuantile(1e-8, 4), -131.59473694062356},
+		{"t(12) 0.6", probability.TDistQuantile(0.6, 12), 0.25903274567688706},
+		{"F(3,20) 0.95", probability.FDistQuantile(0.95, 3, 20), 3.0983912121407799},
+		{"F(10,10) 0.99", probability.FDistQuantile(0.99, 10, 10), 4.8491468020800266},
+		{"F(5,7) 0.01", probability.FDistQuantile(0.01, 5, 7), 0.095643341616908975},
+		{"F(1,1) 0.5", probability.FDistQuantile(0.5, 1, 1), 1},
+		{"gamma(25.5,2) P⁻¹", probability.GammaQuantile(0.13312255797599271, 25.5, 2), 10},
+		{"beta(10,20) I⁻¹", probability.BetaQuantile(0.36400408107194426, 10, 20), 0.3},
+		{"N 1e-20", probability.StandardNormalQuantile(1e-20), -9.262340089798407},
+		{"N 1e-5", probability.StandardNormalQuantile(1e-5), -4.2648907939228247},
+		{"N 0.025", probability.StandardNormalQuantile(0.025), -1.9599639845400543},
+		{"N 0.3", probability.StandardNormalQuantile(0.3), -0.52440051270804078},
+		{"N 0.501", probability.StandardNormalQuantile(0.501), 0.002506630899571764},
+		{"N 0.9", probability.StandardNormalQuantile(0.9), 1.2815515655446004},
+		{"N(3, 2) 0.9", probability.NormalQuantile(0.9, 3, 2), 3 + 2*1.2815515655446004},
+	}
+	for _, c := range cases {
+		if e := relErr(c.got, c.want); e > 1e-12 {
+			t.Errorf("%s = %.17g, want %.17g (relative error %g)", c.name, c.got, c.want, e)
+		}
+	}
+	if e := relErr(probability.ChiSquareCDF(43.772971825742189, 30), 0.95); e > 1e-12 {
+		t.Errorf("ChiSquareCDF relative error %g", e)
+	}
+	if e := relErr(probability.TDistCDF(-131.59473694062356, 4), 1e-8); e > 1e-12 {
+		t.Errorf("TDistCDF relative error %g", e)
+	}
+	if e := relErr(probability.FDistCDF(0.095643341616908975, 5, 7), 0.01); e > 1e-12 {
+		t.Errorf("FDistCDF relative error %g", e)
+	}
+	if e := relErr(probability.BetaCDF(0.3, 10, 20), 0.36400408107194426); e > 1e-12 {
+		t.Errorf("BetaCDF relative error %g", e)
+	}
+}
+
+func TestHypothesisTests(t *testing.T) {
+	// Student's sleep data and R's PlantGrowth; expected values from R.
+	g1 := []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	g2 := []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+	welch := probability.WelchTTest(g1, g2, probability.TwoSided, 0.95)
+	if abs(welch.Statistic+1.8608) > 1e-4 || abs(welch.DF-17.776) > 1e-3 || abs(welch.PValue-0.07939) > 1e-5 ||
+		abs(welch.CILower+3.3654832) > 1e-6 || abs(welch.CIUpper-0.2054832) > 1e-6 {
+		t.Errorf("Welch t-test = %+v", welch)
+	}
+	paired := probability.PairedTTest(g1, g2, probability.TwoSided, 0.95)
+	if abs(paired.Statistic+4.0621) > 1e-4 || paired.DF != 9 || abs(paired.PValue-0.002833) > 1e-6 ||
+		abs(paired.CILower+2.4598858) > 1e-6 || abs(paired.CIUpper+0.7001142) > 1e-6 {
+		t.Errorf("paired t-test = %+v", paired)
+	}
+	less := probability.PairedTTest(g1, g2, probability.Less, 0.95)
+	greater := probability.PairedTTest(g1, g2, probability.Greater, 0.95)
+	if abs(less.PValue-paired.PValue/2) > 1e-15 || abs(less.PValue+greater.PValue-1) > 1e-15 || !math.IsInf(less.CILower, -1) || !math.IsInf(greater.CIUpper, 1) {
+		t.Errorf("one-sided paired tests: less %+v, greater %+v", less, greater)
+	}
+	one := probability.OneSampleTTest(g1, 0, probability.TwoSided, 0.95)
+	sd := math.Sqrt(probability.SampleVariance(g1))
+	if abs(one.Statistic-0.75/(sd/math.Sqrt(10))) > 1e-12 || abs(one.EffectSize-0.75/sd) > 1e-12 || !(one.CILower < 0 && one.CIUpper > 0.75) {
+		t.Errorf("one-sample t-test = %+v", one)
+	}
+
+	ctrl := []float64{4.17, 5.58, 5.18, 6.11, 4.50, 4.61, 5.17, 4.53, 5.33, 5.14}
+	trt1 := []float64{4.81, 4.17, 4.41, 3.59, 5.87, 3.83, 6.03, 4.89, 4.32, 4.69}
+	trt2 := []float64{6.31, 5.12, 5.54, 5.50, 5.37, 5.29, 4.92, 6.15, 5.80, 5.26}
+	anova := probability.OneWayANOVA(ctrl, trt1, trt2)
+	if abs(anova.Statistic-4.846) > 1e-3 || anova.DF != 2 || anova.DF2 != 27 || abs(anova.PValue-0.01591) > 1e-5 || abs(anova.Statistic-probability.ANOVA(ctrl, trt1, trt2)) > 1e-6 {
+		t.Errorf("one-way ANOVA = %+v", anova)
+	}
+
+	table := [][]float64{{762, 327, 468}, {484, 239, 477}}
+	indep := probability.ChiSquareIndependence(table)
+	if abs(indep.Statistic-30.07) > 5e-3 || indep.DF != 2 || abs(indep.PValue-2.954e-7) > 1e-10 {
+		t.Errorf("chi-square independence = %+v", indep)
+	}
+	gof := probability.ChiSquareGoodnessOfFit([]float64{10, 20, 30}, []float64{1, 1, 1})
+	if abs(gof.Statistic-10) > 1e-12 || gof.DF != 2 || abs(gof.PValue-math.Exp(-5)) > 1e-14 || abs(gof.EffectSize-math.Sqrt(10.0/60)) > 1e-14 {
+		t.Errorf("chi-square goodness of fit = %+v", gof)
+	}
+
+	two := probability.TwoProportionZTest(45, 120, 30, 110, probability.TwoSided, 0.95)
+	cross := probability.ChiSquareIndependence([][]float64{{45, 75}, {30, 80}})
+	if abs(two.Statistic*two.Statistic-cross.Statistic) > 1e-10 || abs(two.PValue-cross.PValue) > 1e-12 || !(two.CILower < two.Estimate && two.Estimate < two.CIUpper) {
+		t.Errorf("two-proportion z-test %+v disagrees with the 2x2 chi-square %+v", two, cross)
+	}
+	prop := probability.OneProportionZTest(56, 100, 0.5, probability.TwoSided, 0.95)
+	if abs(prop.Statistic-1.2) > 1e-12 || abs(prop.PValue-2*probability.StandardNormalCDF(-1.2)) > 1e-6 {
+		t.Errorf("one-proportion z-test = %+v", prop)
+	}
+	z := probability.StandardNormalQuantile(0.975)
+	for _, bound := range []float64{prop.CILower, prop.CIUpper} {
+		if lhs, rhs := (0.56-bound)*(0.56-bound), z*z*bound*(1-bound)/100; abs(lhs-rhs) > 1e-12 {
+			t.Errorf("Wilson bound %g does not solve the score equation", bound)
+		}
+	}
+}
+
+func TestNonparametricTests(t *testing.T) {
+	// Expected values from R's examples for wilcox.test, cor.test,
+	// kruskal.test and shapiro.test unless noted.
+	x := []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	y := []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+	if r := probability.WilcoxonPairedSignedRank(x, y, probability.Greater); r.Statistic != 40 || abs(r.PValue-0.01953) > 1e-5 {
+		t.Errorf("Wilcoxon signed-rank = %+v", r)
+	}
+	a := []float64{0.80, 0.83, 1.89, 1.04, 1.45, 1.38, 1.91, 1.64, 0.73, 1.46}
+	b := []float64{1.15, 0.88, 0.90, 0.74, 1.21}
+	if r := probability.MannWhitneyU(a, b, probability.Greater); r.Statistic != 35 || abs(r.PValue-0.1272) > 1e-4 {
+		t.Errorf("Mann-Whitney U = %+v", r)
+	}
+	tiedX := []float64{1.1, 2.0, 2.0, 3.5, 4.2, 6.0, 2.0}
+	tiedY := []float64{2.0, 3.5, 3.5, 5.1, 6.0, 7.3, 8.0, 9.9}
+	if r := probability.MannWhitneyU(tiedX, tiedY, probability.TwoSided); r.Statistic != 11 || relErr(r.PValue, 0.05291632179097916) > 1e-12 {
+		t.Errorf("tied Mann-Whitney U = %+v", r)
+	}
+
+	k1 := []float64{44.4, 45.9, 41.9, 53.3, 44.7, 44.1, 50.7, 45.2, 60.1}
+	k2 := []float64{2.6, 3.1, 2.5, 5.0, 3.6, 4.0, 5.2, 2.8, 3.8}
+	if r := probability.KendallTest(k1, k2, probability.Greater); r.Statistic != 26 || abs(r.PValue-0.05972) > 1e-5 || abs(r.Estimate-4.0/9) > 1e-15 {
+		t.Errorf("Kendall test = %+v", r)
+	}
+	if r := probability.SpearmanTest(k1, k2, probability.Greater); r.Statistic != 48 || abs(r.PValue-0.0484) > 1e-4 || abs(r.Estimate-0.6) > 1e-15 {
+		t.Errorf("Spearman test = %+v", r)
+	}
+	tied := probability.KendallTest([]float64{1, 2, 2, 3, 4, 4, 5, 6}, []float64{2, 1, 3, 3, 5, 4, 6, 6}, probability.TwoSided)
+	if relErr(tied.Statistic, 2.805880733831437) > 1e-12 || relErr(tied.PValue, 0.00501792614621811) > 1e-9 || relErr(tied.Estimate, 11.0/13) > 1e-12 {
+		t.Errorf("tied Kendall test = %+v", tied)
+	}
+
+	// Exact Kruskal-Wallis p-value checked by enumerating all 252252 splits.
+	kw := probability.KruskalWallis([]float64{2.9, 3.0, 2.5, 2.6, 3.2}, []float64{3.8, 2.7, 4.0, 2.4}, []float64{2.8, 3.4, 3.7, 2.2, 2.0})
+	if abs(kw.Statistic-0.77143) > 1e-5 || kw.DF != 2 || abs(kw.PValue-0.7107733536304965) > 1e-12 {
+		t.Errorf("Kruskal-Wallis = %+v", kw)
+	}
+
+	// K(10, 0.274) = 0.6284796154565043 from Marsaglia, Tsang and Wang (2003).
+	u := []float64{0.05, 0.15, 0.18, 0.2, 0.226, 0.55, 0.65, 0.75, 0.85, 0.95}
+	ks := probability.KolmogorovSmirnov(u, func(v float64) float64 { return v }, probability.TwoSided)
+	if abs(ks.Statistic-0.274) > 1e-15 || abs(ks.PValue-(1-0.6284796154565043)) > 1e-13 {
+		t.Errorf("one-sample KS = %+v", ks)
+	}
+	// Two-sample p-values checked by counting lattice paths exactly.
+	s1 := []float64{0.61, 0.29, 0.06, 0.59, -1.73, -0.74, 0.51, -0.56, 0.39, 1.64, 0.05, -0.06, 0.64, -0.82, 0.37, 1.77, 1.09, -1.28, 2.36, 1.31}
+	s2 := []float64{1.05, 0.71, 0.52, 1.53, 2.2, 1.9, 0.04, 1.6, 0.93, 1.28, 1.54, 2.61}
+	two := probability.KolmogorovSmirnovTwoSample(s1, s2, probability.TwoSided)
+	greater := probability.KolmogorovSmirnovTwoSample(s1, s2, probability.Greater)
+	if abs(two.Statistic-7.0/12) > 1e-15 || relErr(two.PValue, 0.00702433256962444) > 1e-12 || relErr(greater.PValue, 0.00351216628481222) > 1e-12 {
+		t.Errorf("two-sample KS: two-sided %+v, greater %+v", two, greater)
+	}
+
+	seq := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if r := probability.ShapiroWilk(seq); abs(r.Statistic-0.97016) > 1e-5 || abs(r.PValue-0.8924) > 1e-4 {
+		t.Errorf("Shapiro-Wilk = %+v", r)
+	}
+	skewed := []float64{0.1, 0.2, 0.2, 0.3, 0.4, 0.5, 0.7, 1.1, 1.8, 3.2, 5.9, 12.5}
+	for _, n := range []int{0, 1, 2, 5001} {
+		if r := probability.ShapiroWilk(make([]float64, n)); !math.IsNaN(r.Statistic) || !math.IsNaN(r.PValue) {
+			t.Errorf("Shapiro-Wilk with n = %d gave W = %g, p = %g", n, r.Statistic, r.PValue)
+		}
+	}
+	sw, ad := probability.ShapiroWilk(skewed), probability.AndersonDarling(skewed)
+	if sw.PValue > 0.001 || ad.PValue > 0.001 || probability.AndersonDarling(seq).PValue < 0.5 {
+		t.Errorf("normality tests on skewed data: Shapiro-Wilk %+v, Anderson-Darling %+v", sw, ad)
+	}
+}
+
+func TestRunningStats(t *testing.T) {
+	rng := probability.NewLCG(7)
+	n := 10000
+	x := make([]float64, n)
+	y := make([]float64, n)
+	for i := range x {
+		x[i] = 1e6 + rng.ExponentialSample(0.5)
+		y[i] = 0.3*x[i] + rng.NormalSample(0, 2)
+	}
+	var all probability.RunningStats
+	var cov probability.RunningCovariance
+	for i := range x {
+		all.Add(x[i])
+		cov.Add(x[i], y[i])
+	}
+	// Partial accumulators of uneven size, merged as goroutines would.
+	var merged probability.RunningStats
+	var mergedCov probability.RunningCovariance
+	for _, bounds := range [][2]int{{0, 1}, {1, 2500}, {2500, 2500}, {2500, 9000}, {9000, n}} {
+		var part probability.RunningStats
+		var partCov probability.RunningCovariance
+		for i := bounds[0]; i < bounds[1]; i++ {
+			part.Add(x[i])
+			partCov.Add(x[i], y[i])
+		}
+		merged.Merge(part)
+		mergedCov.Merge(partCov)
+	}
+	for _, s := range []*probability.RunningStats{&all, &merged} {
+		if s.Count() != n || relErr(s.Mean(), probability.Mean(x)) > 1e-14 ||
+			relErr(s.Variance(), probability.Variance(x)) > 1e-9 ||
+			relErr(s.SampleVariance(), probability.SampleVariance(x)) > 1e-9 ||
+			relErr(s.Skewness(), probability.Skewness(x)) > 1e-8 ||
+			relErr(s.Kurtosis(), probability.Kurtosis(x)) > 1e-8 {
+			t.Errorf("running stats mean %g var %g skew %g kurt %g", s.Mean(), s.Variance(), s.Skewness(), s.Kurtosis())
+		}
+		if s.Min() != minOf(x) || s.Max() != maxOf(x) {
+			t.Errorf("running min/max = %g, %g", s.Min(), s.Max())
+		}
+	}
+	for _, c := range []*probability.RunningCovariance{&cov, &mergedCov} {
+		if c.Count() != n || relErr(c.Covariance(), probability.Covariance(x, y)) > 1e-9 ||
+			relErr(c.SampleCovariance(), probability.SampleCovariance(x, y)) > 1e-9 ||
+			relErr(c.Correlation(), probability.Correlation(x, y)) > 1e-9 {
+			t.Errorf("running covariance %g, correlation %g", c.Covariance(), c.Correlation())
+		}
+	}
+
+	// Negative deviations must count: a symmetric sample has no skew.
+	if sk := probability.Skewness([]float64{1, 2, 3, 4, 5}); abs(sk) > 1e-15 {
+		t.Errorf("Skewness of a symmetric sample = %g", sk)
+	}
+
+	var empty probability.RunningStats
+	empty.Merge(probability.RunningStats{})
+	if empty.Count() != 0 || empty.Variance() != 0 || empty.Skewness() != 0 {
+		t.Errorf("empty accumulator = %+v", empty)
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		all.Add(1.5)
+		cov.Add(1.5, 2.5)
+	}); allocs != 0 {
+		t.Errorf("Add allocates %g times per observation", allocs)
+	}
+}
+
+func minOf(v []float64) float64 {
+	m := v[0]
+	for _, x := range v {
+		m = math.Min(m, x)
+	}
+	return m
+}
+
+func maxOf(v []float64) float64 {
+	m := v[0]
+	for _, x := range v {
+		m = math.Max(m, x)
+	}
+	return m
+}
+
+func TestTDigest(t *testing.T) {
+	rng := probability.NewLCG(11)
+	const compression = 100
+	n := 200000
+	data := make([]float64, n)
+	whole := probability.NewTDigest(compression)
+	parts := make([]*probability.TDigest, 8)
+	for i := range parts {
+		parts[i] = probability.NewTDigest(compression)
+	}
+	for i := range data {
+		// Log-normal latencies: a long right tail.
+		data[i] = math.Exp(rng.NormalSample(3, 1))
+		whole.Add(data[i])
+		parts[i%len(parts)].Add(data[i])
+	}
+	merged := probability.NewTDigest(compression)
+	for _, p := range parts {
+		merged.Merge(p)
+	}
+	sorted := append([]float64{}, data...)
+	sort.Float64s(sorted)
+	rank := func(x float64) float64 {
+		return float64(sort.SearchFloat64s(sorted, x)) / float64(n)
+	}
+	for _, d := range []*probability.TDigest{whole, merged} {
+		if d.Count() != n || d.Size() > compression+2 || d.Min() != sorted[0] || d.Max() != sorted[n-1] {
+			t.Errorf("digest count %d, size %d, range [%g, %g]", d.Count(), d.Size(), d.Min(), d.Max())
+		}
+		for _, p := range []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999} {
+			bound := 2 * math.Pi * math.Sqrt(p*(1-p)) / compression
+			if err := abs(rank(d.Quantile(p)) - p); err > bound/2 {
+				t.Errorf("Quantile(%g) has rank error %g, bound %g", p, err, bound)
+			}
+			x := sorted[int(p*float64(n))]
+			if err := abs(d.CDF(x) - rank(x)); err > bound/2 {
+				t.Errorf("CDF at the %g quantile has error %g, bound %g", p, err, bound)
+			}
+		}
+		if d.Quantile(0) != sorted[0] || d.Quantile(1) != sorted[n-1] || d.CDF(sorted[0]-1) != 0 || d.CDF(sorted[n-1]) != 1 {
+			t.Errorf("digest extremes are not exact")
+		}
+	}
+
+	encoded, err := merged.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded probability.TDigest
+	if err := decoded.UnmarshalBinary(encoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []float64{0.01, 0.5, 0.99} {
+		if decoded.Quantile(p) != merged.Quantile(p) {
+			t.Errorf("decoded Quantile(%g) = %g, want %g", p, decoded.Quantile(p), merged.Quantile(p))
+		}
+	}
+	decoded.Add(1e9)
+	if decoded.Count() != n+1 || decoded.Max() != 1e9 {
+		t.Errorf("decoded digest does not accept new points")
+	}
+	if err := decoded.UnmarshalBinary(encoded[:len(encoded)-3]); err == nil {
+		t.Errorf("truncated encoding decoded without error")
+	}
+	// Malformed headers and centroids must be rejected rather than panic.
+	patch := func(offset int, v uint64, length int) []byte {
+		out := append([]byte{}, encoded[:length]...)
+		binary.LittleEndian.PutUint64(out[offset:], v)
+		return out
+	}
+	for name, bad := range map[string][]byte{
+		"huge centroid count": patch(33, 1<<60, 41),
+		"zero compression":    patch(1, math.Float64bits(0), len(encoded)),
+		"NaN count":           patch(9, math.Float64bits(math.NaN()), len(encoded)),
+		"negative count":      patch(9, math.Float64bits(-1), len(encoded)),
+		"negative weight":     patch(49, math.Float64bits(-2), len(encoded)),
+		"infinite weight":     patch(49, math.Float64bits(math.Inf(1)), len(encoded)),
+	} {
+		var d probability.TDigest
+		if err := d.UnmarshalBinary(bad); err == nil {
+			t.Errorf("%s decoded without error", name)
+		}
+	}
+	if q := probability.NewTDigest(compression).Quantile(0.5); !math.IsNaN(q) {
+		t.Errorf("empty digest Quantile = %g", q)
+	}
+}
+
+func TestRandomGenerators(t *testing.T) {
+	pcg, std := random.NewPCG64(1, 2), rand.NewPCG(1, 2)
+	for i := 0; i < 1000; i++ {
+		if a, b := pcg.Uint64(), std.Uint64(); a != b {
+			t.Fatalf("PCG64 output %d = %#x, math/rand/v2 gives %#x", i, a, b)
+		}
+	}
+	stepped, advanced := random.NewPCG64(3, 4), random.NewPCG64(3, 4)
+	for i := 0; i < 1000; i++ {
+		stepped.Uint64()
+	}
+	advanced.Advance(0, 1000)
+	if stepped.Uint64() != advanced.Uint64() {
+		t.Errorf("Advance(0, 1000) differs from 1000 steps")
+	}
+	jumped, halves := random.NewPCG64(5, 6), random.NewPCG64(5, 6)
+	jumped.Jump()
+	halves.Advance(0, 1<<63)
+	halves.Advance(0, 1<<63)
+	if jumped.Uint64() != halves.Uint64() {
+		t.Errorf("PCG64 Jump is not 2^64 steps")
+	}
+	parent := random.NewPCG64(7, 8)
+	child := parent.Split()
+	fresh := random.NewPCG64(7, 8)
+	if child.Uint64() != fresh.Uint64() {
+		t.Errorf("PCG64 Split child does not continue the parent stream")
+	}
+	fresh = random.NewPCG64(7, 8)
+	fresh.Jump()
+	if parent.Uint64() != fresh.Uint64() {
+		t.Errorf("PCG64 Split does not jump the parent")
+	}
+
+	// Reference outputs and jump computed from the state transition matrix
+	// over GF(2).
+	x := random.NewXoshiro256State([4]uint64{1, 2, 3, 4})
+	for i, want := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		if got := x.Uint64(); got != want {
+			t.Errorf("xoshiro256** output %d = %d, want %d", i, got, want)
+		}
+	}
+	x = random.NewXoshiro256State([4]uint64{1, 2, 3, 4})
+	x.Jump()
+	if want := [4]uint64{0x8c7a153956b5f3d1, 0x701f1a713401d85e, 0x6527f66a65469085, 0x8386b786c4408050}; x.State() != want {
+		t.Errorf("xoshiro256** Jump state = %#x, want %#x", x.State(), want)
+	}
+	streams := random.NewXoshiro256(42)
+	a, b := streams.Split(), streams.Split()
+	if a.State() == b.State() || a.Uint64() == b.Uint64() {
+		t.Errorf("split xoshiro256** streams coincide")
+	}
+
+	// The generators plug into math/rand/v2 and into the other modules.
+	if n := rand.New(random.NewXoshiro256(1)).IntN(10); n < 0 || n >= 10 {
+		t.Errorf("rand.New(Xoshiro256).IntN(10) = %d", n)
+	}
+	integral := calculus.MonteCarloIntegrationWithRNG(func(v float64) float64 { return v * v }, 0, 1, 100000, calculus.NewRNGFromSource(random.NewPCG64(9, 9)))
+	if abs(integral-1.0/3) > 0.01 {
+		t.Errorf("Monte Carlo integral with a PCG64 source = %g", integral)
+	}
+	batches := optimization.ShuffledBatches(optimization.NewRNGFromSource(random.NewXoshiro256(3)), 10, 4)
+	if len(batches) != 3 {
+		t.Errorf("ShuffledBatches with a xoshiro256** source = %v", batches)
+	}
+	sourced := optimization.NewRNGFromSource(random.NewXoshiro256(3))
+	for name, call := range map[string]func(){
+		"Derive":   func() { sourced.Derive(1) },
+		"State":    func() { sourced.State() },
+		"SetState": func() { sourced.SetState(1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s on a source-backed RNG should panic", name)
+				}
+			}()
+			call()
+		}()
+	}
+	if v := (probability.Normal{Mu: 0, Sigma: 1}).Sample(random.New(random.NewXoshiro256(5))); math.IsNaN(v) {
+		t.Errorf("probability sampling with a random.Rand gave NaN")
+	}
+}
+
+func TestRandomVariates(t *testing.T) {
+	r := random.New(random.NewXoshiro256(2024))
+	const n = 50000
+	sample := func(draw func() float64) []float64 {
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = draw()
+		}
+		return out
+	}
+	continuous := []struct {
+		name string
+		data []float64
+		dist probability.Distribution
+	}{
+		{"normal", sample(r.Normal), probability.Normal{Mu: 0, Sigma: 1}},
+		{"exponential", sample(r.Exponential), probability.Exponential{Rate: 1}},
+		{"gamma(2.5, 2)", sample(func() float64 { return r.Gamma(2.5, 2) }), probability.Gamma{Shape: 2.5, Rate: 2}},
+		{"gamma(0.3, 1)", sample(func() float64 { return r.Gamma(0.3, 1) }), probability.Gamma{Shape: 0.3, Rate: 1}},
+		{"beta(2, 5)", sample(func() float64 { return r.Beta(2, 5) }), probability.Beta{Alpha: 2, Beta: 5}},
+	}
+	for _, c := range continuous {
+		ks := probability.KolmogorovSmirnov(c.data, c.dist.CDF, probability.TwoSided)
+		if ks.PValue < 1e-3 || relErr(probability.Mean(c.data), c.dist.Mean()) > 0.02 {
+			t.Errorf("%s variates: KS %+v, mean %g", c.name, ks, probability.Mean(c.data))
+		}
+	}
+
+	discrete := []struct {
+		name string
+		draw func() int
+		dist probability.DiscreteDistribution
+	}{
+		{"poisson(4)", func() int { return r.Poisson(4) }, probability.Poisson{Lambda: 4}},
+		{"poisson(50)", func() int { return r.Poisson(50) }, probability.Poisson{Lambda: 50}},
+		{"binomial(20, 0.3)", func() int { return r.Binomial(20, 0.3) }, probability.Binomial{N: 20, P: 0.3}},
+		{"binomial(1000, 0.4)", func() int { return r.Binomial(1000, 0.4) }, probability.Binomial{N: 1000, P: 0.4}},
+		{"binomial(200, 0.9)", func() int { return r.Binomial(200, 0.9) }, probability.Binomial{N: 200, P: 0.9}},
+	}
+	for _, c := range discrete {
+		// Count each value in the central 99.8% of the target, then pool
+		// neighbours until every expected count is at least 20.
+		lo, hi := c.dist.Quantile(0.001), c.dist.Quantile(0.999)
+		observed := make([]float64, hi-lo+3)
+		for i := 0; i < n; i++ {
+			k := c.draw()
+			switch {
+			case k < lo:
+				observed[0]++
+			case k > hi:
+				observed[len(observed)-1]++
+			default:
+				observed[k-lo+1]++
+			}
+		}
+		expected := make([]float64, len(observed))
+		expected[0] = c.dist.CDF(lo - 1)
+		expected[len(expected)-1] = c.dist.Survival(hi)
+		for k := lo; k <= hi; k++ {
+			expected[k-lo+1] = c.dist.PMF(k)
+		}
+		var obs, exp []float64
+		accObs, accExp := 0.0, 0.0
+		for i := range observed {
+			accObs += observed[i]
+			accExp += expected[i]
+			if accExp*n >= 20 {
+				obs, exp = append(obs, accObs), append(exp, accExp)
+				accObs, accExp = 0, 0
+			}
+		}
+		obs[len(obs)-1] += accObs
+		exp[len(exp)-1] += accExp
+		if gof := probability.ChiSquareGoodnessOfFit(obs, exp); gof.PValue < 1e-3 {
+			t.Errorf("%s variates fail the chi-square test: %+v", c.name, gof)
+		}
+	}
+
+	counts := make([]float64, 7)
+	for i := 0; i < 70000; i++ {
+		counts[r.IntN(7)]++
+	}
+	if gof := probability.ChiSquareGoodnessOfFit(counts, []float64{1, 1, 1, 1, 1, 1, 1}); gof.PValue < 1e-3 {
+		t.Errorf("IntN(7) is not uniform: %v", counts)
+	}
+}
+
+func TestOLS(t *testing.T) {
+	// R's cars data; expected values from summary(lm(dist ~ speed, cars)).
+	speed := []float64{4, 4, 7, 7, 8, 9, 10, 10, 10, 11, 11, 12, 12, 12, 12, 13, 13, 13, 13, 14, 14, 14, 14, 15, 15, 15, 16, 16, 17, 17, 17, 18, 18, 18, 18, 19, 19, 19, 20, 20, 20, 20, 20, 22, 23, 24, 24, 24, 24, 25}
+	dist := []float64{2, 10, 4, 22, 16, 10, 18, 26, 34, 17, 28, 14, 20, 24, 28, 26, 34, 34, 46, 26, 36, 60, 80, 20, 26, 54, 32, 40, 32, 40, 50, 42, 56, 76, 84, 36, 46, 68, 32, 48, 52, 56, 64, 66, 54, 70, 92, 93, 120, 85}
+	X := make([][]float64, len(speed))
+	for i, s := range speed {
+		X[i] = []float64{s}
+	}
+	fit, err := probability.OLS(X, dist, true, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ got, want, tol float64 }{
+		{fit.Coefficients[0], -17.5791, 1e-4}, {fit.Coefficients[1], 3.9324, 1e-4},
+		{fit.StdErrors[0], 6.7584, 1e-4}, {fit.StdErrors[1], 0.4155, 1e-4},
+		{fit.TStats[0], -2.601, 1e-3}, {fit.TStats[1], 9.464, 1e-3},
+		{fit.PValues[0], 0.0123, 1e-4}, {fit.PValues[1], 1.49e-12, 1e-14},
+		{fit.CILower[1], 3.096964, 1e-6}, {fit.CIUpper[1], 4.767853, 1e-6},
+		{fit.ResidualStdError, 15.38, 5e-3}, {fit.RSquared, 0.6511, 1e-4}, {fit.AdjRSquared, 0.6438, 1e-4},
+		{fit.FStatistic, 89.57, 5e-3}, {fit.DFResidual, 48, 0}, {fit.DFModel, 1, 0}, {fit.VIF[0], 1, 0},
+	}
+	for i, w := range want {
+		if abs(w.got-w.want) > w.tol {
+			t.Errorf("cars fit value %d = %g, want %g", i, w.got, w.want)
+		}
+	}
+
+	// Two correlated predictors: check the diagnostics against refits.
+	rng := probability.NewLCG(3)
+	n := 40
+	X = make([][]float64, n)
+	y := make([]float64, n)
+	for i := range X {
+		a := rng.Uniform(0, 10)
+		b := 0.6*a + rng.NormalSample(0, 2)
+		X[i] = []float64{a, b}
+		y[i] = 1.5 + 0.8*a - 1.2*b + rng.NormalSample(0, 1)
+	}
+	fit, err = probability.OLS(X, y, true, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := make([]float64, n), make([]float64, n)
+	for i, row := range X {
+		a[i], b[i] = row[0], row[1]
+	}
+	r := probability.Correlation(a, b)
+	if relErr(fit.VIF[0], 1/(1-r*r)) > 1e-10 || relErr(fit.VIF[1], fit.VIF[0]) > 1e-10 {
+		t.Errorf("VIF = %v, want %g for both", fit.VIF, 1/(1-r*r))
+	}
+	sumLeverage := 0.0
+	for _, h := range fit.Leverage {
+		sumLeverage += h
+	}
+	if abs(sumLeverage-3) > 1e-10 {
+		t.Errorf("leverage sums to %g, want 3", sumLeverage)
+	}
+	s2 := fit.ResidualStdError * fit.ResidualStdError
+	for _, drop := range []int{0, 17, n - 1} {
+		rest := append(append([][]float64{}, X[:drop]...), X[drop+1:]...)
+		restY := append(append([]float64{}, y[:drop]...), y[drop+1:]...)
+		loo, err := probability.OLS(rest, restY, true, 0.95)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shift := 0.0
+		for _, row := range X {
+			d := (fit.Coefficients[1]-loo.Coefficients[1])*row[0] + (fit.Coefficients[2]-loo.Coefficients[2])*row[1] + fit.Coefficients[0] - loo.Coefficients[0]
+			shift += d * d
+		}
+		if cook := shift / (3 * s2); relErr(fit.CooksDistance[drop], cook) > 1e-8 {
+			t.Errorf("Cook's distance %d = %g, leave-one-out gives %g", drop, fit.CooksDistance[drop], cook)
+		}
+	}
+
+	// The QR solve agrees with the normal equations on a well-conditioned
+	// polynomial fit, and the no-intercept model uses the uncentred R².
+	xs := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	ys := []float64{2.1, 3.9, 8.2, 15.8, 26.1, 37.9, 50.2, 65.8}
+	poly := probability.PolynomialRegression(xs, ys, 2)
+	P := make([][]float64, len(xs))
+	for i, v := range xs {
+		P[i] = []float64{v, v * v}
+	}
+	pfit, _ := probability.OLS(P, ys, true, 0.95)
+	for j := range poly {
+		if relErr(pfit.Coefficients[j], poly[j]) > 1e-8 {
+			t.Errorf("coefficient %d = %g, normal equations give %g", j, pfit.Coefficients[j], poly[j])
+		}
+	}
+	origin, _ := probability.OLS(P, ys, false, 0.95)
+	if origin.DFModel != 2 || origin.DFResidual != 6 || origin.RSquared <= pfit.RSquared {
+		t.Errorf("no-intercept fit: df %g/%g, R² %g", origin.DFModel, origin.DFResidual, origin.RSquared)
+	}
+	if _, err := probability.OLS([][]float64{{1, 2}, {2, 4}, {3, 6}, {4, 8}}, []float64{1, 2, 3, 5}, true, 0.95); err == nil {
+		t.Errorf("collinear design fitted without error")
+	}
+}
+
+func TestGLM(t *testing.T) {
+	near := func(a, b []float64, tol float64) bool {
+		for i := range a {
+			if abs(a[i]-b[i]) > tol {
+				return false
+			}
+		}
+		return len(a) == len(b)
+	}
+
+	// Dobson's Poisson example from R's ?glm:
+	// glm(counts ~ outcome + treatment, family = poisson()).
+	counts := []float64{18, 17, 15, 20, 10, 20, 25, 13, 12}
+	X := make([][]float64, len(counts))
+	for i := range X {
+		outcome, treatment := i%3, i/3
+		X[i] = []float64{0, 0, 0, 0}
+		if outcome > 0 {
+			X[i][outcome-1] = 1
+		}
+		if treatment > 0 {
+			X[i][1+treatment] = 1
+		}
+	}
+	fit, err := probability.GLM(X, counts, probability.PoissonFamily, probability.DefaultGLMSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ got, want, tol float64 }{
+		{fit.Coefficients[0], math.Log(21), 1e-8}, {fit.Coefficients[1], math.Log(40.0 / 63), 1e-8},
+		{fit.Coefficients[2], math.Log(47.0 / 63), 1e-8}, {fit.Coefficients[3], 0, 1e-8},
+		{fit.StdErrors[0], 0.1709, 1e-4}, {fit.StdErrors[1], 0.2022, 1e-4}, {fit.StdErrors[2], 0.1927, 1e-4},
+		{fit.StdErrors[3], 0.2000, 1e-4}, {fit.Deviance, 5.1291, 1e-4}, {fit.NullDeviance, 10.5814, 1e-4},
+		{fit.AIC, 56.76, 5e-3}, {fit.DFResidual, 4, 0}, {fit.DFNull, 8, 0}, {fit.Dispersion, 1, 0},
+	}
+	for i, w := range want {
+		if abs(w.got-w.want) > w.tol {
+			t.Errorf("Dobson value %d = %g, want %g", i, w.got, w.want)
+		}
+	}
+	if !fit.Converged || abs(fit.Fitted[0]-21) > 1e-6 {
+		t.Errorf("Dobson fit: converged %v, fitted %v", fit.Converged, fit.Fitted[:3])
+	}
+
+	// Logistic regression on one binary predictor reproduces the 2×2 table:
+	// intercept log(a/b), slope the log odds ratio with SE √(Σ1/cell).
+	a, b, c, d := 12.0, 28.0, 25.0, 15.0
+	var bx [][]float64
+	var by []float64
+	for _, cell := range []struct{ x, y, n float64 }{{0, 1, a}, {0, 0, b}, {1, 1, c}, {1, 0, d}} {
+		for k := 0; k < int(cell.n); k++ {
+			bx = append(bx, []float64{cell.x})
+			by = append(by, cell.y)
+		}
+	}
+	logit, err := probability.GLM(bx, by, probability.BinomialFamily, probability.DefaultGLMSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	logOR, seOR := math.Log(c*b/(a*d)), math.Sqrt(1/a+1/b+1/c+1/d)
+	if abs(logit.Coefficients[0]-math.Log(a/b)) > 1e-8 || abs(logit.Coefficients[1]-logOR) > 1e-8 || abs(logit.StdErrors[1]-seOR) > 1e-8 {
+		t.Errorf("logistic fit %v ± %v, want log OR %g ± %g", logit.Coefficients, logit.StdErrors, logOR, seOR)
+	}
+	// The same data as proportions weighted by trials gives the same fit.
+	settings := probability.DefaultGLMSettings()
+	settings.Weights = []float64{a + b, c + d}
+	grouped, err := probability.GLM([][]float64{{0}, {1}}, []float64{a / (a + b), c / (c + d)}, probability.BinomialFamily, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(grouped.Coefficients, logit.Coefficients, 1e-8) || !near(grouped.StdErrors, logit.StdErrors, 1e-8) || abs(grouped.Deviance) > 1e-8 {
+		t.Errorf("grouped logistic fit %v ± %v, want %v ± %v", grouped.Coefficients, grouped.StdErrors, logit.Coefficients, logit.StdErrors)
+	}
+	if p := logit.Predict([][]float64{{0}, {1}}, nil); !near(p, []float64{a / (a + b), c / (c + d)}, 1e-8) {
+		t.Errorf("predicted probabilities %v", p)
+	}
+
+	// A continuous logistic fit satisfies the score equations, with the L2
+	// penalty shifting them to Xᵀ(y-μ) = λβ for the non-intercept terms.
+	rng := probability.NewLCG(11)
+	n := 200
+	lx := make([][]float64, n)
+	ly := make([]float64, n)
+	for i := range lx {
+		lx[i] = []float64{rng.NormalSample(0, 1), rng.NormalSample(0, 1)}
+		if rng.Float64() < 1/(1+math.Exp(-(0.3+1.2*lx[i][0]-0.7*lx[i][1]))) {
+			ly[i] = 1
+		}
+	}
+	for _, lambda := range []float64{0, 5} {
+		settings := probability.DefaultGLMSettings()
+		settings.L2 = lambda
+		fit, err := probability.GLM(lx, ly, probability.BinomialFamily, settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		score := make([]float64, 3)
+		for i, row := range lx {
+			r := ly[i] - fit.Fitted[i]
+			score[0] += r
+			score[1] += r * row[0]
+			score[2] += r * row[1]
+		}
+		wantScore := []float64{0, lambda * fit.Coefficients[1], lambda * fit.Coefficients[2]}
+		if !near(score, wantScore, 1e-6) {
+			t.Errorf("L2 = %g: score %v, want %v", lambda, score, wantScore)
+		}
+	}
+
+	// A Poisson rate model with a log-exposure offset and no predictors
+	// estimates log(Σy / Σexposure).
+	exposure := []float64{120, 80, 310, 45, 210}
+	events := []float64{4, 1, 9, 2, 5}
+	settings = probability.DefaultGLMSettings()
+	settings.Offset = make([]float64, len(exposure))
+	empty := make([][]float64, len(exposure))
+	for i, e := range exposure {
+		settings.Offset[i] = math.Log(e)
+		empty[i] = []float64{}
+	}
+	rate, err := probability.GLM(empty, events, probability.PoissonFamily, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if abs(rate.Coefficients[0]-math.Log(21.0/765)) > 1e-8 || abs(rate.Deviance-rate.NullDeviance) > 1e-8 {
+		t.Errorf("rate model %v, deviance %g vs null %g", rate.Coefficients, rate.Deviance, rate.NullDeviance)
+	}
+
+	// Gamma with a log link on one factor fits the group means, and the
+	// dispersion is the Pearson statistic over the residual df.
+	gy := []float64{2.1, 3.4, 1.8, 2.9, 6.5, 4.8, 7.9, 5.6}
+	gx := [][]float64{{0}, {0}, {0}, {0}, {1}, {1}, {1}, {1}}
+	gamma, err := probability.GLM(gx, gy, probability.GammaFamily, probability.DefaultGLMSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m0, m1 := 10.2/4, 24.8/4
+	pearson := 0.0
+	for i, v := range gy {
+		m := m0
+		if i >= 4 {
+			m = m1
+		}
+		pearson += (v - m) * (v - m) / (m * m)
+	}
+	if abs(gamma.Coefficients[0]-math.Log(m0)) > 1e-8 || abs(gamma.Coefficients[1]-math.Log(m1/m0)) > 1e-8 || abs(gamma.Dispersion-pearson/6) > 1e-10 {
+		t.Errorf("gamma fit %v, dispersion %g, want %g", gamma.Coefficients, gamma.Dispersion, pearson/6)
+	}
+	if se := math.Sqrt(gamma.Dispersion / 4); abs(gamma.StdErrors[0]-se) > 1e-8 {
+		t.Errorf("gamma intercept SE %g, want %g", gamma.StdErrors[0], se)
+	}
+
+	if _, err := probability.GLM(gx, []float64{1, 2, 0, 1, 3, 2, 1, 4}, probability.GammaFamily, probability.DefaultGLMSettings()); err == nil {
+		t.Errorf("gamma fit accepted a zero response")
+	}
+}
+
+func TestDensityEstimation(t *testing.T) {
+	// Bin counts for 1..20 agree with numpy.histogram_bin_edges.
+	seq := make([]float64, 20)
+	for i := range seq {
+		seq[i] = float64(i + 1)
+	}
+	for rule, want := range map[probability.BinRule]int{
+		probability.SturgesBins: 6, probability.ScottBins: 3,
+		probability.FreedmanDiaconisBins: 3, probability.DoaneBins: 6,
+	} {
+		if got := probability.BinCount(seq, rule); got != want {
+			t.Errorf("BinCount(1..20, %d) = %d, want %d", rule, got, want)
+		}
+	}
+	h := probability.NewHistogramBins(seq, 4)
+	if !sameVector(h.Edges, []float64{1, 5.75, 10.5, 15.25, 20}) || h.Counts[0]+h.Counts[1]+h.Counts[2] != 15 || h.Counts[3] != 5 || h.Counts[0] != 5 || h.Counts[1] != 5 {
+		t.Errorf("histogram %v %v", h.Edges, h.Counts)
+	}
+	rng := probability.NewLCG(5)
+	sample := make([]float64, 500)
+	for i := range sample {
+		sample[i] = rng.NormalSample(0, 1)
+		if i%5 == 0 {
+			sample[i] = math.Exp(sample[i]) * 3
+		}
+	}
+	for _, rule := range []probability.BinRule{probability.SturgesBins, probability.ScottBins, probability.FreedmanDiaconisBins, probability.DoaneBins} {
+		h := probability.NewHistogram(sample, rule)
+		total, area := 0, 0.0
+		for i, d := range h.Density() {
+			total += h.Counts[i]
+			area += d * (h.Edges[i+1] - h.Edges[i])
+		}
+		if total != len(sample) || abs(area-1) > 1e-12 || len(h.Counts) != probability.BinCount(sample, rule) {
+			t.Errorf("rule %d: %d bins hold %d points with area %g", rule, len(h.Counts), total, area)
+		}
+	}
+	if probability.BinCount(sample, probability.DoaneBins) <= probability.BinCount(sample, probability.SturgesBins) {
+		t.Errorf("Doane should add bins for skewed data")
+	}
+
+	// R: bw.nrd0(1:20) and bw.nrd(1:20).
+	if abs(probability.SilvermanBandwidth(seq)-2.9246273193) > 1e-9 || abs(probability.ScottBandwidth(seq)-3.4445610650) > 1e-9 {
+		t.Errorf("bandwidths %g %g", probability.SilvermanBandwidth(seq), probability.ScottBandwidth(seq))
+	}
+
+	// Every kernel is a density with unit variance.
+	kernels := []probability.Kernel{probability.GaussianKernel, probability.EpanechnikovKernel, probability.UniformKernel,
+		probability.TriangularKernel, probability.BiweightKernel, probability.CosineKernel}
+	for _, k := range kernels {
+		mass, second := 0.0, 0.0
+		du := 1e-4
+		for u := -8 + du/2; u < 8; u += du {
+			mass += k.Eval(u) * du
+			second += u * u * k.Eval(u) * du
+		}
+		// The uniform kernel's jumps limit the midpoint rule to O(du).
+		tol := 1e-6
+		if k == probability.UniformKernel {
+			tol = 1e-3
+		}
+		if abs(mass-1) > tol || abs(second-1) > tol {
+			t.Errorf("kernel %d: mass %g, variance %g", k, mass, second)
+		}
+	}
+
+	// A single point with the Gaussian kernel is a normal density.
+	single := probability.NewKDE([]float64{1}, probability.GaussianKernel, 2)
+	if want := (probability.Normal{Mu: 1, Sigma: 2}).PDF(0.3); relErr(single.Density(0.3), want) > 1e-12 {
+		t.Errorf("single-point KDE %g, want %g", single.Density(0.3), want)
+	}
+
+	// The FFT grid agrees with direct evaluation up to the binning error,
+	// which is first order at the uniform kernel's jumps, and integrates to
+	// one when it covers the data.
+	lo, hi := minOf(sample)-4, maxOf(sample)+4
+	for _, k := range kernels {
+		kde := probability.NewKDE(sample, k, 0)
+		xs, ys := kde.Grid(lo, hi, 2048)
+		tol := 2e-3
+		if k == probability.UniformKernel {
+			tol = 5e-2
+		}
+		peak, area := 0.0, 0.0
+		for i, x := range xs {
+			peak = math.Max(peak, kde.Density(x))
+			area += ys[i] * (xs[1] - xs[0])
+		}
+		for i, x := range xs {
+			if d := kde.Density(x); abs(ys[i]-d) > tol*peak {
+				t.Errorf("kernel %d: grid %g vs direct %g at %g", k, ys[i], d, x)
+				break
+			}
+		}
+		if abs(area-1) > tol {
+			t.Errorf("kernel %d: grid integrates to %g", k, area)
+		}
+	}
+
+	// A distant outlier must not stretch the binning grid: only points within
+	// the kernel's reach are binned, while every point still counts in 1/n.
+	outlier := append(append([]float64{}, sample[:100]...), 2e4)
+	for _, k := range []probability.Kernel{probability.GaussianKernel, probability.EpanechnikovKernel} {
+		kde := probability.NewKDE(outlier, k, 0.3)
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		xs, ys := kde.Grid(0, 1, 101)
+		runtime.ReadMemStats(&after)
+		if grown := after.TotalAlloc - before.TotalAlloc; grown > 1<<24 {
+			t.Errorf("kernel %d: narrow grid allocated %d bytes", k, grown)
+		}
+		for i, x := range xs {
+			if d := kde.Density(x); abs(ys[i]-d) > 2e-3*d+1e-12 {
+				t.Errorf("kernel %d: grid %g vs direct %g at %g with an outlier", k, ys[i], d, x)
+				break
+			}
+		}
+	}
+
+	// The 2D estimate is a product of kernels, matches on its grid and
+	// integrates to one.
+	xs, ys := make([]float64, 200), make([]float64, 200)
+	for i := range xs {
+		xs[i] = rng.NormalSample(0, 1)
+		ys[i] = 0.5*xs[i] + rng.NormalSample(0, 1)
+	}
+	kde2 := probability.NewKDE2D(xs, ys, probability.GaussianKernel, 0, 0)
+	if want := probability.StandardDeviation(xs) * math.Sqrt(200.0/199) * math.Pow(200, -1.0/6); relErr(kde2.BandwidthX, want) > 1e-12 {
+		t.Errorf("2D bandwidth %g", kde2.BandwidthX)
+	}
+	gx, gy, z := kde2.Grid(-5, 5, 81, -6, 6, 97)
+	area := 0.0
+	for i := range gx {
+		for j := range gy {
+			area += z[i][j] * (gx[1] - gx[0]) * (gy[1] - gy[0])
+		}
+	}
+	if abs(area-1) > 1e-3 || abs(z[40][48]-kde2.Density(gx[40], gy[48])) > 1e-12 {
+		t.Errorf("2D grid integrates to %g; z = %g vs %g", area, z[40][48], kde2.Density(gx[40], gy[48]))
+	}
+	point := probability.NewKDE2D([]float64{0}, []float64{0}, probability.EpanechnikovKernel, 1, 2)
+	if want := probability.EpanechnikovKernel.Eval(0.5) * probability.EpanechnikovKernel.Eval(0.25) / 2; abs(point.Density(0.5, 0.5)-want) > 1e-15 {
+		t.Errorf("2D product kernel %g, want %g", point.Density(0.5, 0.5), want)
+	}
+}
+
+func TestResampling(t *testing.T) {
+	rng := probability.NewLCG(21)
+	data := make([]float64, 60)
+	for i := range data {
+		data[i] = rng.ExponentialSample(0.5)
+	}
+	settings := probability.DefaultBootstrapSettings()
+	settings.Workers = 1
+	serial := probability.Bootstrap(data, probability.Median, settings)
+	settings.Workers = 8
+	parallel := probability.Bootstrap(data, probability.Median, settings)
+	if !sameVector(serial.Replicates, parallel.Replicates) || serial.BCa != parallel.BCa || serial.Studentized != parallel.Studentized {
+		t.Errorf("bootstrap depends on the worker count")
+	}
+	settings.Seed++
+	if other := probability.Bootstrap(data, probability.Median, settings); sameVector(other.Replicates, serial.Replicates) {
+		t.Errorf("bootstrap ignores the seed")
+	}
+	settings.Seed--
+
+	// For the mean the bootstrap standard error approaches the plug-in σ/√n,
+	// and the basic interval reflects the percentile one about the estimate.
+	mean := probability.Bootstrap(data, probability.Mean, settings)
+	plugIn := probability.StandardDeviation(data) / math.Sqrt(float64(len(data)))
+	if relErr(mean.StdError, plugIn) > 0.08 || abs(mean.Bias) > 0.2*plugIn {
+		t.Errorf("bootstrap SE %g bias %g, want about %g and 0", mean.StdError, mean.Bias, plugIn)
+	}
+	if relErr(mean.Basic.Lower, 2*mean.Estimate-mean.Percentile.Upper) > 1e-12 {
+		t.Errorf("basic %v is not the reflected percentile %v", mean.Basic, mean.Percentile)
+	}
+	for name, iv := range map[string]probability.Interval{"percentile": mean.Percentile, "basic": mean.Basic, "BCa": mean.BCa, "studentized": mean.Studentized} {
+		if !(iv.Lower < mean.Estimate && mean.Estimate < iv.Upper) || relErr(iv.Upper-iv.Lower, 2*1.96*plugIn) > 0.25 {
+			t.Errorf("%s interval %v around %g", name, iv, mean.Estimate)
+		}
+	}
+	// Right-skewed data shift BCa and studentized intervals upward relative
+	// to the percentile interval.
+	if mean.BCa.Upper <= mean.Percentile.Upper || mean.Studentized.Upper <= mean.Percentile.Upper {
+		t.Errorf("skew not reflected: percentile %v, BCa %v, studentized %v", mean.Percentile, mean.BCa, mean.Studentized)
+	}
+	// Percentile intervals respect monotone transformations exactly when the
+	// quantiles fall on order statistics, as 0.025·2000 does.
+	settings.Replicates = 2001
+	mean = probability.Bootstrap(data, probability.Mean, settings)
+	logMean := probability.Bootstrap(data, func(x []float64) float64 { return math.Log(probability.Mean(x)) }, settings)
+	settings.Replicates = 2000
+	if relErr(logMean.Percentile.Lower, math.Log(mean.Percentile.Lower)) > 1e-12 || relErr(logMean.Percentile.Upper, math.Log(mean.Percentile.Upper)) > 1e-12 {
+		t.Errorf("log-mean percentile %v vs %v", logMean.Percentile, mean.Percentile)
+	}
+
+	// Two-sample ratio of means.
+	other := make([]float64, 40)
+	for i := range other {
+		other[i] = rng.ExponentialSample(1)
+	}
+	ratio := probability.BootstrapSamples([][]float64{data, other}, func(s [][]float64) float64 {
+		return probability.Mean(s[0]) / probability.Mean(s[1])
+	}, settings)
+	if ratio.Estimate != probability.Mean(data)/probability.Mean(other) || !(ratio.BCa.Lower < ratio.Estimate && ratio.Estimate < ratio.BCa.Upper) {
+		t.Errorf("ratio %g with BCa %v", ratio.Estimate, ratio.BCa)
+	}
+
+	// The jackknife is exact for the mean and bias-corrects the plug-in
+	// variance to the sample variance.
+	jm := probability.Jackknife(data, probability.Mean)
+	n := float64(len(data))
+	sampleVar := probability.Variance(data) * n / (n - 1)
+	if abs(jm.Bias) > 1e-12 || relErr(jm.Variance, sampleVar/n) > 1e-10 {
+		t.Errorf("jackknife of the mean: bias %g, variance %g, want 0 and %g", jm.Bias, jm.Variance, sampleVar/n)
+	}
+	jv := probability.Jackknife(data, probability.Variance)
+	if relErr(jv.Estimate-jv.Bias, sampleVar) > 1e-10 {
+		t.Errorf("bias-corrected variance %g, want %g", jv.Estimate-jv.Bias, sampleVar)
+	}
+
+	// Exact permutation p-values: 1 of the 20 splits of {1..6} is as extreme
+	// as {1,2,3} vs {4,5,6} in one direction, 2 in both.
+	x, y := []float64{1, 2, 3}, []float64{4, 5, 6}
+	if p := probability.PermutationTest(x, y, nil, probability.TwoSided, settings).PValue; abs(p-0.1) > 1e-12 {
+		t.Errorf("exact two-sided p = %g, want 0.1", p)
+	}
+	if p := probability.PermutationTest(x, y, nil, probability.Less, settings).PValue; abs(p-0.05) > 1e-12 {
+		t.Errorf("exact one-sided p = %g, want 0.05", p)
+	}
+	if p := probability.PermutationTest(x, y, nil, probability.Greater, settings).PValue; abs(p-1) > 1e-12 {
+		t.Errorf("exact p for the wrong direction = %g, want 1", p)
+	}
+	// Random splits approach the exact p-value (924 splits of 6 + 6).
+	a := []float64{3.1, 4.2, 2.8, 5.0, 3.9, 4.4}
+	b := []float64{4.9, 5.5, 3.7, 6.1, 5.2, 4.8}
+	exact := probability.PermutationTest(a, b, nil, probability.TwoSided, settings)
+	settings.Replicates = 900
+	sampled := probability.PermutationTest(a, b, nil, probability.TwoSided, settings)
+	settings.Workers = 1
+	if again := probability.PermutationTest(a, b, nil, probability.TwoSided, settings); again.PValue != sampled.PValue {
+		t.Errorf("permutation test depends on the worker count")
+	}
+	if abs(sampled.PValue-exact.PValue) > 0.02 || exact.Statistic != probability.Mean(a)-probability.Mean(b) || exact.DF != 0 || sampled.DF2 != 0 {
+		t.Errorf("sampled p = %g, exact p = %g", sampled.PValue, exact.PValue)
+	}
+}
+
+func TestMCMC(t *testing.T) {
+	// Correlated Gaussian target: means (1, -2), sds (1, 3), correlation 0.8.
+	mu := []float64{1, -2}
+	sd := []float64{1, 3}
+	rho := 0.8
+	logp := func(x []float64) float64 {
+		a, b := (x[0]-mu[0])/sd[0], (x[1]-mu[1])/sd[1]
+		return -(a*a - 2*rho*a*b + b*b) / (2 * (1 - rho*rho))
+	}
+	grad := func(x []float64) []float64 {
+		a, b := (x[0]-mu[0])/sd[0], (x[1]-mu[1])/sd[1]
+		k := 1 - rho*rho
+		return []float64{-(a - rho*b) / (k * sd[0]), -(b - rho*a) / (k * sd[1])}
+	}
+	settings := probability.DefaultMCMCSettings()
+	x0 := []float64{0, 0}
+	runs := map[string]probability.MCMCResult{
+		"MH":    probability.MetropolisHastings(logp, x0, settings),
+		"slice": probability.SliceSampler(logp, x0, settings),
+		"HMC":   probability.HMC(logp, grad, x0, settings),
+		"NUTS":  probability.NUTS(logp, grad, x0, settings),
+	}
+	for name, res := range runs {
+		mean := res.Mean()
+		for p := range mu {
+			var all []float64
+			for _, chain := range res.Draws(p) {
+				all = append(all, chain...)
+			}
+			if se := sd[p] / math.Sqrt(res.ESS[p]); abs(mean[p]-mu[p]) > 4*se {
+				t.Errorf("%s: mean %d = %g, want %g ± %g", name, p, mean[p], mu[p], se)
+			}
+			if relErr(probability.StandardDeviation(all), sd[p]) > 0.1 || res.RHat[p] > 1.02 || res.ESS[p] < 300 {
+				t.Errorf("%s: parameter %d has sd %g, R-hat %g, ESS %g", name, p, probability.StandardDeviation(all), res.RHat[p], res.ESS[p])
+			}
+		}
+		if len(res.Chains) != 4 || len(res.Chains[0]) != settings.Samples {
+			t.Errorf("%s: %d chains of %d draws", name, len(res.Chains), len(res.Chains[0]))
+		}
+		lo, hi := 0.6, 1.0
+		if name == "MH" {
+			lo, hi = 0.15, 0.4
+		}
+		for _, a := range res.AcceptanceRate {
+			if a < lo || a > hi {
+				t.Errorf("%s: acceptance rate %g outside [%g, %g]", name, a, lo, hi)
+			}
+		}
+	}
+	settings.Workers = 1
+	if serial := probability.NUTS(logp, grad, x0, settings); !sameVector(serial.Chains[3][999], runs["NUTS"].Chains[3][999]) {
+		t.Errorf("NUTS depends on the worker count")
+	}
+
+	// Bounded support: a Gamma(3, 1) target returning -Inf below zero.
+	gamma := probability.Gamma{Shape: 3, Rate: 1}
+	logGamma := func(x []float64) float64 {
+		if x[0] <= 0 {
+			return math.Inf(-1)
+		}
+		return gamma.LogPDF(x[0])
+	}
+	for name, res := range map[string]probability.MCMCResult{
+		"MH":    probability.MetropolisHastings(logGamma, []float64{3}, settings),
+		"slice": probability.SliceSampler(logGamma, []float64{3}, settings),
+	} {
+		if se := math.Sqrt(3 / res.ESS[0]); abs(res.Mean()[0]-3) > 4*se {
+			t.Errorf("%s: gamma mean %g ± %g", name, res.Mean()[0], se)
+		}
+	}
+
+	// Diagnostics on known processes: independent normal chains, chains
+	// stuck at different levels and an AR(1) with φ = 0.9, whose lag-k
+	// autocorrelation is φ^k and whose ESS is about N(1-φ)/(1+φ).
+	rng := probability.NewLCG(8)
+	iid, shifted, ar := make([][]float64, 4), make([][]float64, 4), make([][]float64, 4)
+	for c := range iid {
+		iid[c] = make([]float64, 2000)
+		shifted[c] = make([]float64, 2000)
+		ar[c] = make([]float64, 2000)
+		prev := rng.NormalSample(0, 1/math.Sqrt(1-0.81))
+		for i := range iid[c] {
+			iid[c][i] = rng.NormalSample(0, 1)
+			shifted[c][i] = iid[c][i] + float64(c)
+			prev = 0.9*prev + rng.NormalSample(0, 1)
+			ar[c][i] = prev
+		}
+	}
+	if r := probability.SplitRHat(iid); r > 1.01 {
+		t.Errorf("R-hat of independent chains = %g", r)
+	}
+	if r := probability.SplitRHat(shifted); r < 1.5 {
+		t.Errorf("R-hat of separated chains = %g", r)
+	}
+	if ess := probability.EffectiveSampleSize(iid); relErr(ess, 8000) > 0.15 {
+		t.Errorf("ESS of independent draws = %g, want about 8000", ess)
+	}
+	if ess := probability.EffectiveSampleSize(ar); relErr(ess, 8000*0.1/1.9) > 0.3 {
+		t.Errorf("ESS of AR(1) = %g, want about %g", ess, 8000*0.1/1.9)
+	}
+	acf := probability.Autocorrelation(ar[0], 2)
+	if acf[0] != 1 || abs(acf[1]-0.9) > 0.05 || abs(acf[2]-0.81) > 0.08 {
+		t.Errorf("AR(1) autocorrelation %v", acf)
+	}
+	if acf := runs["MH"].Autocorrelation(0, 1); acf[1] <= runs["NUTS"].Autocorrelation(0, 1)[1] {
+		t.Errorf("random-walk draws should be more autocorrelated than NUTS draws")
+	}
+}
+
 //MMMMMMMM               MMMMMMMM     OOOOOOOOO     UUUUUUUU     UUUUUUUU           AAA                              AAA               DDDDDDDDDDDDD        
 //M:::::::M             M:::::::M   OO:::::::::OO   U::::::U     U::::::U          A:::A                            A:::A              D::::::::::::DDD     
 //M::::::::M           M::::::::M OO:::::::::::::OO U::::::U     U::::::U         A:::::A                          A:::::A             D:::::::::::::::DD   